@@ -42,10 +42,13 @@ func (p *Program) String() string {
 	return out.String()
 }
 
+// PlayerStatement binds Value to Name, or, when Pattern is set instead,
+// destructures it into the names in an array or hash pattern.
 type PlayerStatement struct {
-	Token token.Token
-	Name  *Identifier
-	Value Expression
+	Token   token.Token
+	Name    *Identifier
+	Pattern Expression
+	Value   Expression
 }
 
 func (ps *PlayerStatement) statementNode()       {}
@@ -54,7 +57,11 @@ func (ps *PlayerStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(ps.TokenLiteral() + " ")
-	out.WriteString(ps.Name.String())
+	if ps.Pattern != nil {
+		out.WriteString(ps.Pattern.String())
+	} else {
+		out.WriteString(ps.Name.String())
+	}
 	out.WriteString(" = ")
 
 	if ps.Value != nil {
@@ -192,8 +199,9 @@ func (ie *AppealIfExpression) String() string {
 }
 
 type BlockStatement struct {
-	Token      token.Token
+	Token      token.Token // the '{' token
 	Statements []Statement
+	EndToken   token.Token // the closing '}' token
 }
 
 func (bs *BlockStatement) statementNode()       {}
@@ -207,9 +215,11 @@ func (bs *BlockStatement) String() string {
 	return out.String()
 }
 
+// FieldLiteral's Parameters are identifiers, or array and hash patterns
+// that destructure the argument passed in their place.
 type FieldLiteral struct {
 	Token      token.Token
-	Parameters []*Identifier
+	Parameters []Expression
 	Body       *BlockStatement
 }
 
@@ -232,6 +242,31 @@ func (fl *FieldLiteral) String() string {
 	return out.String()
 }
 
+type MacroLiteral struct {
+	Token      token.Token
+	Parameters []*Identifier
+	Body       *BlockStatement
+}
+
+func (ml *MacroLiteral) expressionNode()      {}
+func (ml *MacroLiteral) TokenLiteral() string { return ml.Token.Literal }
+func (ml *MacroLiteral) String() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range ml.Parameters {
+		params = append(params, p.String())
+	}
+
+	out.WriteString(ml.TokenLiteral())
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(")")
+	out.WriteString(ml.Body.String())
+
+	return out.String()
+}
+
 type CallExpression struct {
 	Token     token.Token // the '(' token
 	Function  Expression
@@ -286,6 +321,33 @@ func (al *ArrayLiteral) String() string {
 	return out.String()
 }
 
+// HashLiteral keeps its pairs in source order.
+type HashLiteral struct {
+	Token token.Token // the '{' token
+	Pairs []HashPair
+}
+
+type HashPair struct {
+	Key   Expression
+	Value Expression
+}
+
+func (hl *HashLiteral) expressionNode()      {}
+func (hl *HashLiteral) TokenLiteral() string { return hl.Token.Literal }
+func (hl *HashLiteral) String() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+	for _, pair := range hl.Pairs {
+		pairs = append(pairs, pair.Key.String()+": "+pair.Value.String())
+	}
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
 type IndexExpression struct {
 	Token token.Token
 	Left  Expression
@@ -305,3 +367,124 @@ func (ie *IndexExpression) String() string {
 
 	return out.String()
 }
+
+// SquadStatement declares a squad, a type whose Fields are filled in by
+// calling it. Body holds the methods, each a player bound to a field.
+type SquadStatement struct {
+	Token  token.Token // the 'squad' token
+	Name   *Identifier
+	Fields []*Identifier
+	Parent *Identifier // nil unless the squad extends another
+	Body   *BlockStatement
+}
+
+func (ss *SquadStatement) statementNode()       {}
+func (ss *SquadStatement) TokenLiteral() string { return ss.Token.Literal }
+func (ss *SquadStatement) String() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for _, f := range ss.Fields {
+		fields = append(fields, f.String())
+	}
+
+	out.WriteString(ss.TokenLiteral() + " ")
+	out.WriteString(ss.Name.String())
+	out.WriteString("(")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString(")")
+	if ss.Parent != nil {
+		out.WriteString(" extends " + ss.Parent.String())
+	}
+	out.WriteString(" {")
+	out.WriteString(ss.Body.String())
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// DotExpression reads a field or method, as in card.runs.
+type DotExpression struct {
+	Token token.Token // the '.' token
+	Left  Expression
+	Name  *Identifier
+}
+
+func (de *DotExpression) expressionNode()      {}
+func (de *DotExpression) TokenLiteral() string { return de.Token.Literal }
+func (de *DotExpression) String() string {
+	return de.Left.String() + "." + de.Name.String()
+}
+
+// AssignExpression sets a field, as in self.runs = 4. The parser only
+// accepts a DotExpression as the Target.
+type AssignExpression struct {
+	Token  token.Token // the '=' token
+	Target Expression
+	Value  Expression
+}
+
+func (ae *AssignExpression) expressionNode()      {}
+func (ae *AssignExpression) TokenLiteral() string { return ae.Token.Literal }
+func (ae *AssignExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ae.Target.String())
+	out.WriteString(" = ")
+	out.WriteString(ae.Value.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// UmpireExpression matches Subject against each arm's pattern in turn and
+// evaluates the body of the first arm that matches.
+type UmpireExpression struct {
+	Token    token.Token // the 'umpire' token
+	Subject  Expression
+	Arms     []UmpireArm
+	EndToken token.Token // the '}' closing the arms
+}
+
+// UmpireArm is one `pattern appeal (guard) -> body` arm. Patterns are
+// literals, identifiers to bind (`_` matches anything), and array and hash
+// literals of patterns. Guard is nil when the arm has none.
+type UmpireArm struct {
+	Pattern Expression
+	Guard   Expression
+	Body    Expression
+}
+
+func (ue *UmpireExpression) expressionNode()      {}
+func (ue *UmpireExpression) TokenLiteral() string { return ue.Token.Literal }
+func (ue *UmpireExpression) String() string {
+	var out bytes.Buffer
+
+	arms := []string{}
+	for _, arm := range ue.Arms {
+		s := arm.Pattern.String()
+		if arm.Guard != nil {
+			s += " appeal " + arm.Guard.String()
+		}
+		arms = append(arms, s+" -> "+arm.Body.String())
+	}
+	out.WriteString("umpire (")
+	out.WriteString(ue.Subject.String())
+	out.WriteString(") {")
+	out.WriteString(strings.Join(arms, "; "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// RestElement is the `...name` that ends an array pattern and collects the
+// elements left over.
+type RestElement struct {
+	Token token.Token // the '...' token
+	Name  *Identifier
+}
+
+func (re *RestElement) expressionNode()      {}
+func (re *RestElement) TokenLiteral() string { return re.Token.Literal }
+func (re *RestElement) String() string       { return "..." + re.Name.String() }
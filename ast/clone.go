@@ -0,0 +1,327 @@
+package ast
+
+// Clone returns a deep copy of node. Tokens are copied by value, so the
+// copy can be rewritten freely without touching the original tree.
+func Clone(node Node) Node {
+	switch node := node.(type) {
+	case *Program:
+		return &Program{Statements: cloneStatements(node.Statements)}
+	case *PlayerStatement:
+		return &PlayerStatement{
+			Token:   node.Token,
+			Name:    cloneIdentifier(node.Name),
+			Pattern: cloneExpression(node.Pattern),
+			Value:   cloneExpression(node.Value),
+		}
+	case *SignalDecisionStatement:
+		return &SignalDecisionStatement{
+			Token:               node.Token,
+			SignalDecisionValue: cloneExpression(node.SignalDecisionValue),
+		}
+	case *ExpressionStatement:
+		return &ExpressionStatement{Token: node.Token, Expression: cloneExpression(node.Expression)}
+	case *BlockStatement:
+		return cloneBlock(node)
+	case *Identifier:
+		return cloneIdentifier(node)
+	case *IntegerLiteral:
+		return &IntegerLiteral{Token: node.Token, Value: node.Value}
+	case *StringLiteral:
+		return &StringLiteral{Token: node.Token, Value: node.Value}
+	case *Boolean:
+		return &Boolean{Token: node.Token, Value: node.Value}
+	case *PrefixExpression:
+		return &PrefixExpression{
+			Token:    node.Token,
+			Operator: node.Operator,
+			Right:    cloneExpression(node.Right),
+		}
+	case *InfixExpression:
+		return &InfixExpression{
+			Token:    node.Token,
+			Left:     cloneExpression(node.Left),
+			Operator: node.Operator,
+			Right:    cloneExpression(node.Right),
+		}
+	case *AppealIfExpression:
+		return &AppealIfExpression{
+			Token:       node.Token,
+			Condition:   cloneExpression(node.Condition),
+			Consequence: cloneBlock(node.Consequence),
+			Alternative: cloneBlock(node.Alternative),
+		}
+	case *FieldLiteral:
+		return &FieldLiteral{
+			Token:      node.Token,
+			Parameters: cloneExpressions(node.Parameters),
+			Body:       cloneBlock(node.Body),
+		}
+	case *MacroLiteral:
+		return &MacroLiteral{
+			Token:      node.Token,
+			Parameters: cloneIdentifiers(node.Parameters),
+			Body:       cloneBlock(node.Body),
+		}
+	case *CallExpression:
+		return &CallExpression{
+			Token:     node.Token,
+			Function:  cloneExpression(node.Function),
+			Arguments: cloneExpressions(node.Arguments),
+		}
+	case *ArrayLiteral:
+		return &ArrayLiteral{Token: node.Token, Elements: cloneExpressions(node.Elements)}
+	case *HashLiteral:
+		pairs := make([]HashPair, len(node.Pairs))
+		for i, pair := range node.Pairs {
+			pairs[i] = HashPair{Key: cloneExpression(pair.Key), Value: cloneExpression(pair.Value)}
+		}
+		return &HashLiteral{Token: node.Token, Pairs: pairs}
+	case *IndexExpression:
+		return &IndexExpression{
+			Token: node.Token,
+			Left:  cloneExpression(node.Left),
+			Index: cloneExpression(node.Index),
+		}
+	case *SquadStatement:
+		return &SquadStatement{
+			Token:  node.Token,
+			Name:   cloneIdentifier(node.Name),
+			Fields: cloneIdentifiers(node.Fields),
+			Parent: cloneIdentifier(node.Parent),
+			Body:   cloneBlock(node.Body),
+		}
+	case *DotExpression:
+		return &DotExpression{Token: node.Token, Left: cloneExpression(node.Left), Name: cloneIdentifier(node.Name)}
+	case *AssignExpression:
+		return &AssignExpression{
+			Token:  node.Token,
+			Target: cloneExpression(node.Target),
+			Value:  cloneExpression(node.Value),
+		}
+	case *UmpireExpression:
+		arms := make([]UmpireArm, len(node.Arms))
+		for i, arm := range node.Arms {
+			arms[i] = UmpireArm{
+				Pattern: cloneExpression(arm.Pattern),
+				Guard:   cloneExpression(arm.Guard),
+				Body:    cloneExpression(arm.Body),
+			}
+		}
+		return &UmpireExpression{Token: node.Token, Subject: cloneExpression(node.Subject), Arms: arms, EndToken: node.EndToken}
+	case *RestElement:
+		return &RestElement{Token: node.Token, Name: cloneIdentifier(node.Name)}
+	}
+	return nil
+}
+
+func cloneStatements(stmts []Statement) []Statement {
+	if stmts == nil {
+		return nil
+	}
+	result := make([]Statement, len(stmts))
+	for i, s := range stmts {
+		if s != nil {
+			result[i], _ = Clone(s).(Statement)
+		}
+	}
+	return result
+}
+
+func cloneExpressions(exps []Expression) []Expression {
+	if exps == nil {
+		return nil
+	}
+	result := make([]Expression, len(exps))
+	for i, e := range exps {
+		result[i] = cloneExpression(e)
+	}
+	return result
+}
+
+func cloneExpression(exp Expression) Expression {
+	if exp == nil {
+		return nil
+	}
+	result, _ := Clone(exp).(Expression)
+	return result
+}
+
+func cloneIdentifiers(idents []*Identifier) []*Identifier {
+	if idents == nil {
+		return nil
+	}
+	result := make([]*Identifier, len(idents))
+	for i, ident := range idents {
+		result[i] = cloneIdentifier(ident)
+	}
+	return result
+}
+
+func cloneIdentifier(ident *Identifier) *Identifier {
+	if ident == nil {
+		return nil
+	}
+	return &Identifier{Token: ident.Token, Value: ident.Value}
+}
+
+func cloneBlock(block *BlockStatement) *BlockStatement {
+	if block == nil {
+		return nil
+	}
+	return &BlockStatement{
+		Token:      block.Token,
+		Statements: cloneStatements(block.Statements),
+		EndToken:   block.EndToken,
+	}
+}
+
+// Equal reports whether a and b are structurally the same tree: the same
+// node kinds with the same operators, values and children. Tokens are not
+// compared, so a hand-built node equals a parsed one.
+func Equal(a, b Node) bool {
+	if isNilNode(a) || isNilNode(b) {
+		return isNilNode(a) && isNilNode(b)
+	}
+
+	switch a := a.(type) {
+	case *Program:
+		b, ok := b.(*Program)
+		return ok && equalStatements(a.Statements, b.Statements)
+	case *PlayerStatement:
+		b, ok := b.(*PlayerStatement)
+		return ok && Equal(a.Name, b.Name) && Equal(a.Pattern, b.Pattern) && Equal(a.Value, b.Value)
+	case *SignalDecisionStatement:
+		b, ok := b.(*SignalDecisionStatement)
+		return ok && Equal(a.SignalDecisionValue, b.SignalDecisionValue)
+	case *ExpressionStatement:
+		b, ok := b.(*ExpressionStatement)
+		return ok && Equal(a.Expression, b.Expression)
+	case *BlockStatement:
+		b, ok := b.(*BlockStatement)
+		return ok && equalStatements(a.Statements, b.Statements)
+	case *Identifier:
+		b, ok := b.(*Identifier)
+		return ok && a.Value == b.Value
+	case *IntegerLiteral:
+		b, ok := b.(*IntegerLiteral)
+		return ok && a.Value == b.Value
+	case *StringLiteral:
+		b, ok := b.(*StringLiteral)
+		return ok && a.Value == b.Value
+	case *Boolean:
+		b, ok := b.(*Boolean)
+		return ok && a.Value == b.Value
+	case *PrefixExpression:
+		b, ok := b.(*PrefixExpression)
+		return ok && a.Operator == b.Operator && Equal(a.Right, b.Right)
+	case *InfixExpression:
+		b, ok := b.(*InfixExpression)
+		return ok && a.Operator == b.Operator && Equal(a.Left, b.Left) && Equal(a.Right, b.Right)
+	case *AppealIfExpression:
+		b, ok := b.(*AppealIfExpression)
+		return ok && Equal(a.Condition, b.Condition) &&
+			Equal(a.Consequence, b.Consequence) && Equal(a.Alternative, b.Alternative)
+	case *FieldLiteral:
+		b, ok := b.(*FieldLiteral)
+		return ok && equalExpressions(a.Parameters, b.Parameters) && Equal(a.Body, b.Body)
+	case *MacroLiteral:
+		b, ok := b.(*MacroLiteral)
+		return ok && equalIdentifiers(a.Parameters, b.Parameters) && Equal(a.Body, b.Body)
+	case *CallExpression:
+		b, ok := b.(*CallExpression)
+		return ok && Equal(a.Function, b.Function) && equalExpressions(a.Arguments, b.Arguments)
+	case *ArrayLiteral:
+		b, ok := b.(*ArrayLiteral)
+		return ok && equalExpressions(a.Elements, b.Elements)
+	case *HashLiteral:
+		b, ok := b.(*HashLiteral)
+		if !ok || len(a.Pairs) != len(b.Pairs) {
+			return false
+		}
+		for i := range a.Pairs {
+			if !Equal(a.Pairs[i].Key, b.Pairs[i].Key) || !Equal(a.Pairs[i].Value, b.Pairs[i].Value) {
+				return false
+			}
+		}
+		return true
+	case *IndexExpression:
+		b, ok := b.(*IndexExpression)
+		return ok && Equal(a.Left, b.Left) && Equal(a.Index, b.Index)
+	case *SquadStatement:
+		b, ok := b.(*SquadStatement)
+		return ok && Equal(a.Name, b.Name) && equalIdentifiers(a.Fields, b.Fields) &&
+			Equal(a.Parent, b.Parent) && Equal(a.Body, b.Body)
+	case *DotExpression:
+		b, ok := b.(*DotExpression)
+		return ok && Equal(a.Left, b.Left) && Equal(a.Name, b.Name)
+	case *AssignExpression:
+		b, ok := b.(*AssignExpression)
+		return ok && Equal(a.Target, b.Target) && Equal(a.Value, b.Value)
+	case *UmpireExpression:
+		b, ok := b.(*UmpireExpression)
+		if !ok || !Equal(a.Subject, b.Subject) || len(a.Arms) != len(b.Arms) {
+			return false
+		}
+		for i := range a.Arms {
+			if !Equal(a.Arms[i].Pattern, b.Arms[i].Pattern) || !Equal(a.Arms[i].Guard, b.Arms[i].Guard) ||
+				!Equal(a.Arms[i].Body, b.Arms[i].Body) {
+				return false
+			}
+		}
+		return true
+	case *RestElement:
+		b, ok := b.(*RestElement)
+		return ok && Equal(a.Name, b.Name)
+	}
+	return false
+}
+
+func equalStatements(a, b []Statement) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalIdentifiers(a, b []*Identifier) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalExpressions(a, b []Expression) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNilNode catches both a nil interface and a typed nil pointer such as a
+// missing Alternative block.
+func isNilNode(n Node) bool {
+	switch n := n.(type) {
+	case nil:
+		return true
+	case *BlockStatement:
+		return n == nil
+	case *Identifier:
+		return n == nil
+	}
+	return false
+}
@@ -0,0 +1,533 @@
+package ast
+
+import (
+	"CricLang/token"
+	"encoding/json"
+	"fmt"
+)
+
+// The JSON form of a node is an object with a "kind" naming the Go type,
+// a "token" carrying the literal and position it was parsed from, and one
+// key per field of the node. Missing children are encoded as null.
+
+type jsonToken struct {
+	Type    string `json:"type"`
+	Literal string `json:"literal"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+}
+
+// MarshalJSON encodes node and all of its children.
+func MarshalJSON(node Node) ([]byte, error) {
+	return json.Marshal(encodeNode(node))
+}
+
+// MarshalIndentJSON is like MarshalJSON but indents the output.
+func MarshalIndentJSON(node Node, prefix, indent string) ([]byte, error) {
+	return json.MarshalIndent(encodeNode(node), prefix, indent)
+}
+
+// UnmarshalJSON decodes a node previously encoded with MarshalJSON.
+func UnmarshalJSON(data []byte) (Node, error) {
+	return decodeNode(data)
+}
+
+// UnmarshalProgramJSON decodes data and checks that it holds a Program.
+func UnmarshalProgramJSON(data []byte) (*Program, error) {
+	node, err := decodeNode(data)
+	if err != nil {
+		return nil, err
+	}
+	program, ok := node.(*Program)
+	if !ok {
+		return nil, fmt.Errorf("expected a Program, got %T", node)
+	}
+	return program, nil
+}
+
+func encodeToken(t token.Token) jsonToken {
+	return jsonToken{Type: string(t.Type), Literal: t.Literal, Line: t.Line, Column: t.Column}
+}
+
+func encodeNode(node Node) interface{} {
+	if isNilNode(node) {
+		return nil
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		return map[string]interface{}{
+			"kind":       "Program",
+			"statements": encodeStatements(n.Statements),
+		}
+	case *PlayerStatement:
+		return map[string]interface{}{
+			"kind":    "PlayerStatement",
+			"token":   encodeToken(n.Token),
+			"name":    encodeNode(n.Name),
+			"pattern": encodeExpression(n.Pattern),
+			"value":   encodeExpression(n.Value),
+		}
+	case *SignalDecisionStatement:
+		return map[string]interface{}{
+			"kind":  "SignalDecisionStatement",
+			"token": encodeToken(n.Token),
+			"value": encodeExpression(n.SignalDecisionValue),
+		}
+	case *ExpressionStatement:
+		return map[string]interface{}{
+			"kind":       "ExpressionStatement",
+			"token":      encodeToken(n.Token),
+			"expression": encodeExpression(n.Expression),
+		}
+	case *BlockStatement:
+		return map[string]interface{}{
+			"kind":       "BlockStatement",
+			"token":      encodeToken(n.Token),
+			"statements": encodeStatements(n.Statements),
+			"endToken":   encodeToken(n.EndToken),
+		}
+	case *Identifier:
+		return map[string]interface{}{
+			"kind":  "Identifier",
+			"token": encodeToken(n.Token),
+			"value": n.Value,
+		}
+	case *IntegerLiteral:
+		return map[string]interface{}{
+			"kind":  "IntegerLiteral",
+			"token": encodeToken(n.Token),
+			"value": n.Value,
+		}
+	case *StringLiteral:
+		return map[string]interface{}{
+			"kind":  "StringLiteral",
+			"token": encodeToken(n.Token),
+			"value": n.Value,
+		}
+	case *Boolean:
+		return map[string]interface{}{
+			"kind":  "Boolean",
+			"token": encodeToken(n.Token),
+			"value": n.Value,
+		}
+	case *PrefixExpression:
+		return map[string]interface{}{
+			"kind":     "PrefixExpression",
+			"token":    encodeToken(n.Token),
+			"operator": n.Operator,
+			"right":    encodeExpression(n.Right),
+		}
+	case *InfixExpression:
+		return map[string]interface{}{
+			"kind":     "InfixExpression",
+			"token":    encodeToken(n.Token),
+			"operator": n.Operator,
+			"left":     encodeExpression(n.Left),
+			"right":    encodeExpression(n.Right),
+		}
+	case *AppealIfExpression:
+		return map[string]interface{}{
+			"kind":        "AppealIfExpression",
+			"token":       encodeToken(n.Token),
+			"condition":   encodeExpression(n.Condition),
+			"consequence": encodeNode(n.Consequence),
+			"alternative": encodeNode(n.Alternative),
+		}
+	case *FieldLiteral:
+		return map[string]interface{}{
+			"kind":       "FieldLiteral",
+			"token":      encodeToken(n.Token),
+			"parameters": encodeExpressions(n.Parameters),
+			"body":       encodeNode(n.Body),
+		}
+	case *MacroLiteral:
+		return map[string]interface{}{
+			"kind":       "MacroLiteral",
+			"token":      encodeToken(n.Token),
+			"parameters": encodeIdentifiers(n.Parameters),
+			"body":       encodeNode(n.Body),
+		}
+	case *CallExpression:
+		return map[string]interface{}{
+			"kind":      "CallExpression",
+			"token":     encodeToken(n.Token),
+			"function":  encodeExpression(n.Function),
+			"arguments": encodeExpressions(n.Arguments),
+		}
+	case *ArrayLiteral:
+		return map[string]interface{}{
+			"kind":     "ArrayLiteral",
+			"token":    encodeToken(n.Token),
+			"elements": encodeExpressions(n.Elements),
+		}
+	case *HashLiteral:
+		keys, values := []Expression{}, []Expression{}
+		for _, pair := range n.Pairs {
+			keys = append(keys, pair.Key)
+			values = append(values, pair.Value)
+		}
+		return map[string]interface{}{
+			"kind":   "HashLiteral",
+			"token":  encodeToken(n.Token),
+			"keys":   encodeExpressions(keys),
+			"values": encodeExpressions(values),
+		}
+	case *IndexExpression:
+		return map[string]interface{}{
+			"kind":  "IndexExpression",
+			"token": encodeToken(n.Token),
+			"left":  encodeExpression(n.Left),
+			"index": encodeExpression(n.Index),
+		}
+	case *SquadStatement:
+		return map[string]interface{}{
+			"kind":   "SquadStatement",
+			"token":  encodeToken(n.Token),
+			"name":   encodeNode(n.Name),
+			"fields": encodeIdentifiers(n.Fields),
+			"parent": encodeNode(n.Parent),
+			"body":   encodeNode(n.Body),
+		}
+	case *DotExpression:
+		return map[string]interface{}{
+			"kind":  "DotExpression",
+			"token": encodeToken(n.Token),
+			"left":  encodeExpression(n.Left),
+			"name":  encodeNode(n.Name),
+		}
+	case *AssignExpression:
+		return map[string]interface{}{
+			"kind":   "AssignExpression",
+			"token":  encodeToken(n.Token),
+			"target": encodeExpression(n.Target),
+			"value":  encodeExpression(n.Value),
+		}
+	case *UmpireExpression:
+		patterns, guards, bodies := []Expression{}, []Expression{}, []Expression{}
+		for _, arm := range n.Arms {
+			patterns = append(patterns, arm.Pattern)
+			guards = append(guards, arm.Guard)
+			bodies = append(bodies, arm.Body)
+		}
+		return map[string]interface{}{
+			"kind":     "UmpireExpression",
+			"token":    encodeToken(n.Token),
+			"subject":  encodeExpression(n.Subject),
+			"patterns": encodeExpressions(patterns),
+			"guards":   encodeExpressions(guards),
+			"bodies":   encodeExpressions(bodies),
+			"endToken": encodeToken(n.EndToken),
+		}
+	case *RestElement:
+		return map[string]interface{}{
+			"kind":  "RestElement",
+			"token": encodeToken(n.Token),
+			"name":  encodeNode(n.Name),
+		}
+	}
+	return nil
+}
+
+func encodeStatements(stmts []Statement) []interface{} {
+	result := []interface{}{}
+	for _, s := range stmts {
+		result = append(result, encodeNode(s))
+	}
+	return result
+}
+
+func encodeIdentifiers(idents []*Identifier) []interface{} {
+	result := []interface{}{}
+	for _, ident := range idents {
+		result = append(result, encodeNode(ident))
+	}
+	return result
+}
+
+func encodeExpressions(exps []Expression) []interface{} {
+	result := []interface{}{}
+	for _, e := range exps {
+		result = append(result, encodeExpression(e))
+	}
+	return result
+}
+
+func encodeExpression(exp Expression) interface{} {
+	if exp == nil {
+		return nil
+	}
+	return encodeNode(exp)
+}
+
+// jsonFields wraps the raw fields of one encoded node. The first error hit
+// while reading a field sticks, so callers can read every field and check
+// err once at the end.
+type jsonFields struct {
+	kind   string
+	fields map[string]json.RawMessage
+	err    error
+}
+
+func (f *jsonFields) fail(format string, a ...interface{}) {
+	if f.err == nil {
+		f.err = fmt.Errorf(f.kind+": "+format, a...)
+	}
+}
+
+func (f *jsonFields) scalar(key string, v interface{}) {
+	raw, ok := f.fields[key]
+	if !ok {
+		f.fail("missing %q", key)
+		return
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		f.fail("bad %q: %s", key, err)
+	}
+}
+
+func (f *jsonFields) token() token.Token {
+	return f.tokenAt("token")
+}
+
+func (f *jsonFields) tokenAt(key string) token.Token {
+	var t jsonToken
+	f.scalar(key, &t)
+	return token.Token{Type: token.TokenType(t.Type), Literal: t.Literal, Line: t.Line, Column: t.Column}
+}
+
+func (f *jsonFields) node(key string) Node {
+	raw, ok := f.fields[key]
+	if !ok {
+		f.fail("missing %q", key)
+		return nil
+	}
+	n, err := decodeNode(raw)
+	if err != nil {
+		f.fail("%s", err)
+	}
+	return n
+}
+
+func (f *jsonFields) list(key string) []Node {
+	var raws []json.RawMessage
+	f.scalar(key, &raws)
+	if raws == nil {
+		return nil
+	}
+
+	nodes := make([]Node, 0, len(raws))
+	for _, raw := range raws {
+		n, err := decodeNode(raw)
+		if err != nil {
+			f.fail("%s", err)
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+func (f *jsonFields) expression(key string) Expression {
+	return f.asExpression(f.node(key))
+}
+
+// optionalExpression is like expression but lets the key be left out, for
+// fields added after the JSON form was first published.
+func (f *jsonFields) optionalExpression(key string) Expression {
+	if _, ok := f.fields[key]; !ok {
+		return nil
+	}
+	return f.expression(key)
+}
+
+func (f *jsonFields) asExpression(n Node) Expression {
+	if n == nil {
+		return nil
+	}
+	exp, ok := n.(Expression)
+	if !ok {
+		f.fail("%T is not an expression", n)
+	}
+	return exp
+}
+
+func (f *jsonFields) identifier(n Node) *Identifier {
+	if n == nil {
+		return nil
+	}
+	ident, ok := n.(*Identifier)
+	if !ok {
+		f.fail("expected Identifier, got %T", n)
+	}
+	return ident
+}
+
+func (f *jsonFields) identifiers(key string) []*Identifier {
+	idents := []*Identifier{}
+	for _, n := range f.list(key) {
+		idents = append(idents, f.identifier(n))
+	}
+	return idents
+}
+
+func (f *jsonFields) block(key string) *BlockStatement {
+	n := f.node(key)
+	if n == nil {
+		return nil
+	}
+	block, ok := n.(*BlockStatement)
+	if !ok {
+		f.fail("expected BlockStatement for %q, got %T", key, n)
+	}
+	return block
+}
+
+func (f *jsonFields) statements(key string) []Statement {
+	stmts := []Statement{}
+	for _, n := range f.list(key) {
+		stmt, ok := n.(Statement)
+		if !ok {
+			f.fail("%T is not a statement", n)
+			continue
+		}
+		stmts = append(stmts, stmt)
+	}
+	return stmts
+}
+
+func (f *jsonFields) expressions(key string) []Expression {
+	nodes := f.list(key)
+	if nodes == nil {
+		return nil
+	}
+	exps := []Expression{}
+	for _, n := range nodes {
+		exps = append(exps, f.asExpression(n))
+	}
+	return exps
+}
+
+func decodeNode(data []byte) (Node, error) {
+	f := &jsonFields{}
+	if err := json.Unmarshal(data, &f.fields); err != nil {
+		return nil, err
+	}
+	if f.fields == nil {
+		return nil, nil
+	}
+	f.kind = "node"
+	f.scalar("kind", &f.kind)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	var node Node
+
+	switch f.kind {
+	case "Program":
+		node = &Program{Statements: f.statements("statements")}
+	case "PlayerStatement":
+		node = &PlayerStatement{
+			Token:   f.token(),
+			Name:    f.identifier(f.node("name")),
+			Pattern: f.optionalExpression("pattern"),
+			Value:   f.expression("value"),
+		}
+	case "SignalDecisionStatement":
+		node = &SignalDecisionStatement{Token: f.token(), SignalDecisionValue: f.expression("value")}
+	case "ExpressionStatement":
+		node = &ExpressionStatement{Token: f.token(), Expression: f.expression("expression")}
+	case "BlockStatement":
+		node = &BlockStatement{
+			Token:      f.token(),
+			Statements: f.statements("statements"),
+			EndToken:   f.tokenAt("endToken"),
+		}
+	case "Identifier":
+		n := &Identifier{Token: f.token()}
+		f.scalar("value", &n.Value)
+		node = n
+	case "IntegerLiteral":
+		n := &IntegerLiteral{Token: f.token()}
+		f.scalar("value", &n.Value)
+		node = n
+	case "StringLiteral":
+		n := &StringLiteral{Token: f.token()}
+		f.scalar("value", &n.Value)
+		node = n
+	case "Boolean":
+		n := &Boolean{Token: f.token()}
+		f.scalar("value", &n.Value)
+		node = n
+	case "PrefixExpression":
+		n := &PrefixExpression{Token: f.token(), Right: f.expression("right")}
+		f.scalar("operator", &n.Operator)
+		node = n
+	case "InfixExpression":
+		n := &InfixExpression{Token: f.token(), Left: f.expression("left"), Right: f.expression("right")}
+		f.scalar("operator", &n.Operator)
+		node = n
+	case "AppealIfExpression":
+		node = &AppealIfExpression{
+			Token:       f.token(),
+			Condition:   f.expression("condition"),
+			Consequence: f.block("consequence"),
+			Alternative: f.block("alternative"),
+		}
+	case "FieldLiteral":
+		node = &FieldLiteral{Token: f.token(), Parameters: f.expressions("parameters"), Body: f.block("body")}
+	case "MacroLiteral":
+		node = &MacroLiteral{Token: f.token(), Parameters: f.identifiers("parameters"), Body: f.block("body")}
+	case "CallExpression":
+		node = &CallExpression{
+			Token:     f.token(),
+			Function:  f.expression("function"),
+			Arguments: f.expressions("arguments"),
+		}
+	case "ArrayLiteral":
+		node = &ArrayLiteral{Token: f.token(), Elements: f.expressions("elements")}
+	case "HashLiteral":
+		n := &HashLiteral{Token: f.token(), Pairs: []HashPair{}}
+		keys, values := f.expressions("keys"), f.expressions("values")
+		if len(keys) != len(values) {
+			f.fail("%d keys but %d values", len(keys), len(values))
+		}
+		for i := 0; i < len(keys) && i < len(values); i++ {
+			n.Pairs = append(n.Pairs, HashPair{Key: keys[i], Value: values[i]})
+		}
+		node = n
+	case "IndexExpression":
+		node = &IndexExpression{Token: f.token(), Left: f.expression("left"), Index: f.expression("index")}
+	case "SquadStatement":
+		node = &SquadStatement{
+			Token:  f.token(),
+			Name:   f.identifier(f.node("name")),
+			Fields: f.identifiers("fields"),
+			Parent: f.identifier(f.node("parent")),
+			Body:   f.block("body"),
+		}
+	case "DotExpression":
+		node = &DotExpression{Token: f.token(), Left: f.expression("left"), Name: f.identifier(f.node("name"))}
+	case "AssignExpression":
+		node = &AssignExpression{Token: f.token(), Target: f.expression("target"), Value: f.expression("value")}
+	case "UmpireExpression":
+		n := &UmpireExpression{Token: f.token(), Subject: f.expression("subject"), Arms: []UmpireArm{}, EndToken: f.tokenAt("endToken")}
+		patterns, guards, bodies := f.expressions("patterns"), f.expressions("guards"), f.expressions("bodies")
+		if len(patterns) != len(guards) || len(patterns) != len(bodies) {
+			f.fail("%d patterns, %d guards and %d bodies", len(patterns), len(guards), len(bodies))
+		}
+		for i := 0; i < len(patterns) && i < len(guards) && i < len(bodies); i++ {
+			n.Arms = append(n.Arms, UmpireArm{Pattern: patterns[i], Guard: guards[i], Body: bodies[i]})
+		}
+		node = n
+	case "RestElement":
+		node = &RestElement{Token: f.token(), Name: f.identifier(f.node("name"))}
+	default:
+		return nil, fmt.Errorf("unknown node kind %q", f.kind)
+	}
+
+	if f.err != nil {
+		return nil, f.err
+	}
+	return node, nil
+}
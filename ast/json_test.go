@@ -0,0 +1,110 @@
+package ast
+
+import (
+	"CricLang/token"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	program := everyNodeKind()
+	program.Statements = append(program.Statements, &ExpressionStatement{
+		Expression: &AppealIfExpression{Condition: one(), Consequence: block()},
+	}, &SquadStatement{Name: &Identifier{Value: "S"}, Fields: []*Identifier{}, Body: block()})
+
+	data, err := MarshalJSON(program)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %s", err)
+	}
+
+	decoded, err := UnmarshalProgramJSON(data)
+	if err != nil {
+		t.Fatalf("UnmarshalProgramJSON failed: %s", err)
+	}
+
+	if !Equal(program, decoded) {
+		t.Errorf("decoded program differs.\nwant=%s\ngot= %s", program, decoded)
+	}
+
+	again, err := MarshalJSON(decoded)
+	if err != nil {
+		t.Fatalf("MarshalJSON of decoded program failed: %s", err)
+	}
+	if string(again) != string(data) {
+		t.Errorf("re-encoding is not stable.\nwant=%s\ngot= %s", data, again)
+	}
+}
+
+func TestJSONTokens(t *testing.T) {
+	integer := &IntegerLiteral{
+		Token: token.Token{Type: token.INT, Literal: "9223372036854775807", Line: 3, Column: 14},
+		Value: 9223372036854775807,
+	}
+
+	data, err := MarshalJSON(integer)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %s", err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("output is not JSON: %s", err)
+	}
+	if raw["kind"] != "IntegerLiteral" {
+		t.Errorf("wrong kind. got=%v", raw["kind"])
+	}
+
+	decoded, err := UnmarshalJSON(data)
+	if err != nil {
+		t.Fatalf("UnmarshalJSON failed: %s", err)
+	}
+	got, ok := decoded.(*IntegerLiteral)
+	if !ok {
+		t.Fatalf("decoded node is not *IntegerLiteral. got=%T", decoded)
+	}
+	if got.Token != integer.Token || got.Value != integer.Value {
+		t.Errorf("integer not decoded losslessly. want=%+v, got=%+v", integer, got)
+	}
+}
+
+func TestJSONDecodeErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"kind": "Wicket"}`, `unknown node kind "Wicket"`},
+		{`{"statements": []}`, `missing "kind"`},
+		{`{"kind": "Program", "statements": [{"kind": "IntegerLiteral", "token": {}, "value": 1}]}`,
+			"is not a statement"},
+		{`{"kind": "PlayerStatement", "token": {}, "name": {"kind": "Boolean", "token": {}, "value": true}, "value": null}`,
+			"expected Identifier"},
+		{`{"kind": "IntegerLiteral", "token": {}, "value": "five"}`, `bad "value"`},
+	}
+
+	for _, tt := range tests {
+		_, err := UnmarshalJSON([]byte(tt.input))
+		if err == nil {
+			t.Errorf("expected error for %s", tt.input)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("wrong error for %s. want substring %q, got=%q", tt.input, tt.expected, err)
+		}
+	}
+}
+
+func TestJSONPlayerWithoutPattern(t *testing.T) {
+	// JSON written before players could destructure has no "pattern" key
+	input := `{"kind": "PlayerStatement", "token": {}, "name": {"kind": "Identifier", "token": {}, "value": "x"},
+		"value": {"kind": "IntegerLiteral", "token": {}, "value": 1}}`
+
+	decoded, err := UnmarshalJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("UnmarshalJSON failed: %s", err)
+	}
+	expected := &PlayerStatement{Name: &Identifier{Value: "x"}, Value: one()}
+	if !Equal(decoded, expected) {
+		t.Errorf("wrong player. want=%s, got=%s", expected, decoded)
+	}
+}
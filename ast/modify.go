@@ -0,0 +1,137 @@
+package ast
+
+// ModifierFunc is applied to every node by Modify. It returns the node that
+// should take the original's place.
+type ModifierFunc func(Node) Node
+
+// Modify rewrites the tree rooted at node bottom-up: children are modified
+// first, then modifier is called on node itself. A replacement that doesn't
+// fit the slot it would go into (say, an expression where an identifier is
+// required) is ignored and the original kept. Returning nil for a statement
+// inside a Program or BlockStatement removes that statement.
+func Modify(node Node, modifier ModifierFunc) Node {
+	switch node := node.(type) {
+	case *Program:
+		node.Statements = modifyStatements(node.Statements, modifier)
+	case *PlayerStatement:
+		node.Name = modifyIdentifier(node.Name, modifier)
+		node.Pattern = modifyExpression(node.Pattern, modifier)
+		node.Value = modifyExpression(node.Value, modifier)
+	case *SignalDecisionStatement:
+		node.SignalDecisionValue = modifyExpression(node.SignalDecisionValue, modifier)
+	case *ExpressionStatement:
+		node.Expression = modifyExpression(node.Expression, modifier)
+	case *BlockStatement:
+		node.Statements = modifyStatements(node.Statements, modifier)
+	case *PrefixExpression:
+		node.Right = modifyExpression(node.Right, modifier)
+	case *InfixExpression:
+		node.Left = modifyExpression(node.Left, modifier)
+		node.Right = modifyExpression(node.Right, modifier)
+	case *AppealIfExpression:
+		node.Condition = modifyExpression(node.Condition, modifier)
+		node.Consequence = modifyBlock(node.Consequence, modifier)
+		node.Alternative = modifyBlock(node.Alternative, modifier)
+	case *FieldLiteral:
+		modifyExpressions(node.Parameters, modifier)
+		node.Body = modifyBlock(node.Body, modifier)
+	case *MacroLiteral:
+		for i, p := range node.Parameters {
+			node.Parameters[i] = modifyIdentifier(p, modifier)
+		}
+		node.Body = modifyBlock(node.Body, modifier)
+	case *CallExpression:
+		node.Function = modifyExpression(node.Function, modifier)
+		modifyExpressions(node.Arguments, modifier)
+	case *ArrayLiteral:
+		modifyExpressions(node.Elements, modifier)
+	case *HashLiteral:
+		for i, pair := range node.Pairs {
+			node.Pairs[i].Key = modifyExpression(pair.Key, modifier)
+			node.Pairs[i].Value = modifyExpression(pair.Value, modifier)
+		}
+	case *IndexExpression:
+		node.Left = modifyExpression(node.Left, modifier)
+		node.Index = modifyExpression(node.Index, modifier)
+	case *SquadStatement:
+		node.Name = modifyIdentifier(node.Name, modifier)
+		for i, f := range node.Fields {
+			node.Fields[i] = modifyIdentifier(f, modifier)
+		}
+		node.Parent = modifyIdentifier(node.Parent, modifier)
+		node.Body = modifyBlock(node.Body, modifier)
+	case *DotExpression:
+		node.Left = modifyExpression(node.Left, modifier)
+		node.Name = modifyIdentifier(node.Name, modifier)
+	case *AssignExpression:
+		node.Target = modifyExpression(node.Target, modifier)
+		node.Value = modifyExpression(node.Value, modifier)
+	case *UmpireExpression:
+		node.Subject = modifyExpression(node.Subject, modifier)
+		for i, arm := range node.Arms {
+			node.Arms[i].Pattern = modifyExpression(arm.Pattern, modifier)
+			node.Arms[i].Guard = modifyExpression(arm.Guard, modifier)
+			node.Arms[i].Body = modifyExpression(arm.Body, modifier)
+		}
+	case *RestElement:
+		node.Name = modifyIdentifier(node.Name, modifier)
+	}
+
+	return modifier(node)
+}
+
+func modifyStatements(stmts []Statement, modifier ModifierFunc) []Statement {
+	result := []Statement{}
+
+	for _, s := range stmts {
+		if s == nil {
+			continue
+		}
+		modified := Modify(s, modifier)
+		if modified == nil {
+			continue
+		}
+		if stmt, ok := modified.(Statement); ok {
+			result = append(result, stmt)
+		} else {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func modifyExpressions(exps []Expression, modifier ModifierFunc) {
+	for i, e := range exps {
+		exps[i] = modifyExpression(e, modifier)
+	}
+}
+
+func modifyExpression(exp Expression, modifier ModifierFunc) Expression {
+	if exp == nil {
+		return nil
+	}
+	if modified, ok := Modify(exp, modifier).(Expression); ok {
+		return modified
+	}
+	return exp
+}
+
+func modifyIdentifier(ident *Identifier, modifier ModifierFunc) *Identifier {
+	if ident == nil {
+		return nil
+	}
+	if modified, ok := Modify(ident, modifier).(*Identifier); ok {
+		return modified
+	}
+	return ident
+}
+
+func modifyBlock(block *BlockStatement, modifier ModifierFunc) *BlockStatement {
+	if block == nil {
+		return nil
+	}
+	if modified, ok := Modify(block, modifier).(*BlockStatement); ok {
+		return modified
+	}
+	return block
+}
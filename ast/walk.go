@@ -0,0 +1,137 @@
+package ast
+
+// A Visitor's Visit method is invoked for each node encountered by Walk.
+// If the result visitor w is not nil, Walk visits each of the children
+// of node with the visitor w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses an AST in depth-first order, the same way go/ast does.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		walkStatements(v, n.Statements)
+	case *PlayerStatement:
+		walkIdentifier(v, n.Name)
+		walkExpression(v, n.Pattern)
+		walkExpression(v, n.Value)
+	case *SignalDecisionStatement:
+		walkExpression(v, n.SignalDecisionValue)
+	case *ExpressionStatement:
+		walkExpression(v, n.Expression)
+	case *BlockStatement:
+		walkStatements(v, n.Statements)
+	case *PrefixExpression:
+		walkExpression(v, n.Right)
+	case *InfixExpression:
+		walkExpression(v, n.Left)
+		walkExpression(v, n.Right)
+	case *AppealIfExpression:
+		walkExpression(v, n.Condition)
+		walkBlock(v, n.Consequence)
+		walkBlock(v, n.Alternative)
+	case *FieldLiteral:
+		walkExpressions(v, n.Parameters)
+		walkBlock(v, n.Body)
+	case *MacroLiteral:
+		for _, p := range n.Parameters {
+			walkIdentifier(v, p)
+		}
+		walkBlock(v, n.Body)
+	case *CallExpression:
+		walkExpression(v, n.Function)
+		walkExpressions(v, n.Arguments)
+	case *ArrayLiteral:
+		walkExpressions(v, n.Elements)
+	case *HashLiteral:
+		for _, pair := range n.Pairs {
+			walkExpression(v, pair.Key)
+			walkExpression(v, pair.Value)
+		}
+	case *IndexExpression:
+		walkExpression(v, n.Left)
+		walkExpression(v, n.Index)
+	case *SquadStatement:
+		walkIdentifier(v, n.Name)
+		for _, f := range n.Fields {
+			walkIdentifier(v, f)
+		}
+		walkIdentifier(v, n.Parent)
+		walkBlock(v, n.Body)
+	case *DotExpression:
+		walkExpression(v, n.Left)
+		walkIdentifier(v, n.Name)
+	case *AssignExpression:
+		walkExpression(v, n.Target)
+		walkExpression(v, n.Value)
+	case *UmpireExpression:
+		walkExpression(v, n.Subject)
+		for _, arm := range n.Arms {
+			walkExpression(v, arm.Pattern)
+			walkExpression(v, arm.Guard)
+			walkExpression(v, arm.Body)
+		}
+	case *RestElement:
+		walkIdentifier(v, n.Name)
+	case *Identifier, *IntegerLiteral, *StringLiteral, *Boolean:
+		// leaves
+	}
+
+	v.Visit(nil)
+}
+
+// The helpers below skip nil children so visitors never see a typed nil
+// pointer wrapped in a non-nil Node.
+
+func walkStatements(v Visitor, stmts []Statement) {
+	for _, s := range stmts {
+		if s != nil {
+			Walk(v, s)
+		}
+	}
+}
+
+func walkExpressions(v Visitor, exps []Expression) {
+	for _, e := range exps {
+		walkExpression(v, e)
+	}
+}
+
+func walkExpression(v Visitor, exp Expression) {
+	if exp != nil {
+		Walk(v, exp)
+	}
+}
+
+func walkIdentifier(v Visitor, ident *Identifier) {
+	if ident != nil {
+		Walk(v, ident)
+	}
+}
+
+func walkBlock(v Visitor, block *BlockStatement) {
+	if block != nil {
+		Walk(v, block)
+	}
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses an AST in depth-first order: it starts by calling
+// f(node); if f returns true, Inspect invokes f recursively for each of
+// the non-nil children of node, followed by a call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
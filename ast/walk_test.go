@@ -0,0 +1,283 @@
+package ast
+
+import (
+	"reflect"
+	"testing"
+)
+
+func one() Expression { return &IntegerLiteral{Value: 1} }
+func two() Expression { return &IntegerLiteral{Value: 2} }
+
+func block(exps ...Expression) *BlockStatement {
+	stmts := []Statement{}
+	for _, e := range exps {
+		stmts = append(stmts, &ExpressionStatement{Expression: e})
+	}
+	return &BlockStatement{Statements: stmts}
+}
+
+// everyNodeKind builds a tree that contains each node type at least once,
+// with the interesting children all holding the integer 1.
+func everyNodeKind() *Program {
+	return &Program{
+		Statements: []Statement{
+			&PlayerStatement{Name: &Identifier{Value: "x"}, Value: one()},
+			&PlayerStatement{
+				Pattern: &HashLiteral{Pairs: []HashPair{{Key: &StringLiteral{Value: "k"}, Value: &Identifier{Value: "v"}}}},
+				Value:   one(),
+			},
+			&SignalDecisionStatement{SignalDecisionValue: one()},
+			&ExpressionStatement{Expression: &PrefixExpression{Operator: "-", Right: one()}},
+			&ExpressionStatement{Expression: &InfixExpression{Left: one(), Operator: "+", Right: one()}},
+			&ExpressionStatement{Expression: &AppealIfExpression{
+				Condition:   one(),
+				Consequence: block(one()),
+				Alternative: block(one()),
+			}},
+			&ExpressionStatement{Expression: &FieldLiteral{
+				Parameters: []Expression{&Identifier{Value: "a"}, &Identifier{Value: "b"}},
+				Body:       block(one()),
+			}},
+			&ExpressionStatement{Expression: &MacroLiteral{
+				Parameters: []*Identifier{{Value: "m"}},
+				Body:       block(one()),
+			}},
+			&ExpressionStatement{Expression: &CallExpression{
+				Function:  &Identifier{Value: "f"},
+				Arguments: []Expression{one(), one()},
+			}},
+			&ExpressionStatement{Expression: &ArrayLiteral{Elements: []Expression{one(), one()}}},
+			&ExpressionStatement{Expression: &HashLiteral{Pairs: []HashPair{{Key: &StringLiteral{Value: "k"}, Value: one()}}}},
+			&ExpressionStatement{Expression: &IndexExpression{Left: one(), Index: one()}},
+			&ExpressionStatement{Expression: &StringLiteral{Value: "s"}},
+			&ExpressionStatement{Expression: &Boolean{Value: true}},
+			&SquadStatement{
+				Name:   &Identifier{Value: "S"},
+				Fields: []*Identifier{{Value: "f"}},
+				Parent: &Identifier{Value: "P"},
+				Body: &BlockStatement{Statements: []Statement{
+					&PlayerStatement{Name: &Identifier{Value: "m"}, Value: one()},
+				}},
+			},
+			&ExpressionStatement{Expression: &AssignExpression{
+				Target: &DotExpression{Left: one(), Name: &Identifier{Value: "d"}},
+				Value:  one(),
+			}},
+			&ExpressionStatement{Expression: &UmpireExpression{
+				Subject: one(),
+				Arms: []UmpireArm{
+					{
+						Pattern: &ArrayLiteral{Elements: []Expression{one(), &RestElement{Name: &Identifier{Value: "r"}}}},
+						Guard:   one(),
+						Body:    one(),
+					},
+					{Pattern: &Identifier{Value: "_"}, Body: one()},
+				},
+			}},
+		},
+	}
+}
+
+func TestInspect(t *testing.T) {
+	counts := map[string]int{}
+
+	Inspect(everyNodeKind(), func(n Node) bool {
+		if n != nil {
+			counts[reflect.TypeOf(n).Elem().Name()]++
+		}
+		return true
+	})
+
+	expected := map[string]int{
+		"Program":                 1,
+		"PlayerStatement":         3,
+		"SignalDecisionStatement": 1,
+		"ExpressionStatement":     17,
+		"BlockStatement":          5,
+		"Identifier":              13,
+		"IntegerLiteral":          26,
+		"StringLiteral":           3,
+		"Boolean":                 1,
+		"PrefixExpression":        1,
+		"InfixExpression":         1,
+		"AppealIfExpression":      1,
+		"FieldLiteral":            1,
+		"MacroLiteral":            1,
+		"CallExpression":          1,
+		"ArrayLiteral":            2,
+		"HashLiteral":             2,
+		"IndexExpression":         1,
+		"SquadStatement":          1,
+		"DotExpression":           1,
+		"AssignExpression":        1,
+		"UmpireExpression":        1,
+		"RestElement":             1,
+	}
+
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("wrong node counts.\nwant=%v\ngot= %v", expected, counts)
+	}
+}
+
+func TestInspectPrunes(t *testing.T) {
+	visited := 0
+
+	Inspect(everyNodeKind(), func(n Node) bool {
+		if n != nil {
+			visited++
+		}
+		_, isField := n.(*FieldLiteral)
+		return !isField
+	})
+
+	// pruning the field skips its 2 parameters, body block, statement and literal
+	full := 0
+	Inspect(everyNodeKind(), func(n Node) bool {
+		if n != nil {
+			full++
+		}
+		return true
+	})
+
+	if full-visited != 5 {
+		t.Errorf("pruning skipped wrong number of nodes. want=5, got=%d", full-visited)
+	}
+}
+
+func TestModify(t *testing.T) {
+	turnOneIntoTwo := func(node Node) Node {
+		integer, ok := node.(*IntegerLiteral)
+		if !ok || integer.Value != 1 {
+			return node
+		}
+		return two()
+	}
+
+	program := everyNodeKind()
+	modified := Modify(program, turnOneIntoTwo)
+
+	ones := 0
+	Inspect(modified, func(n Node) bool {
+		if integer, ok := n.(*IntegerLiteral); ok && integer.Value == 1 {
+			ones++
+		}
+		return true
+	})
+	if ones != 0 {
+		t.Errorf("Modify left %d ones behind: %s", ones, modified)
+	}
+}
+
+func TestModifyKeepsSlotTypes(t *testing.T) {
+	program := &Program{Statements: []Statement{
+		&PlayerStatement{Name: &Identifier{Value: "x"}, Value: one()},
+		&ExpressionStatement{Expression: &Identifier{Value: "drop"}},
+	}}
+
+	Modify(program, func(node Node) Node {
+		switch node := node.(type) {
+		case *Identifier:
+			// not allowed in the PlayerStatement name slot, so it is ignored
+			return one()
+		case *ExpressionStatement:
+			if _, ok := node.Expression.(*Identifier); ok {
+				return nil
+			}
+		}
+		return node
+	})
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("wrong number of statements. got=%d", len(program.Statements))
+	}
+	if program.Statements[0].(*PlayerStatement).Name.Value != "x" {
+		t.Errorf("player name was replaced. got=%s", program.Statements[0])
+	}
+}
+
+func TestModifyRemovesStatements(t *testing.T) {
+	program := &Program{Statements: []Statement{
+		&ExpressionStatement{Expression: one()},
+		&ExpressionStatement{Expression: two()},
+	}}
+
+	Modify(program, func(node Node) Node {
+		if stmt, ok := node.(*ExpressionStatement); ok && Equal(stmt.Expression, one()) {
+			return nil
+		}
+		return node
+	})
+
+	if len(program.Statements) != 1 || !Equal(program.Statements[0], &ExpressionStatement{Expression: two()}) {
+		t.Errorf("statement not removed. got=%s", program)
+	}
+}
+
+func TestCloneAndEqual(t *testing.T) {
+	original := everyNodeKind()
+	clone := Clone(original)
+
+	if !Equal(original, clone) {
+		t.Fatalf("clone is not equal to original")
+	}
+
+	Modify(clone, func(node Node) Node {
+		if integer, ok := node.(*IntegerLiteral); ok {
+			integer.Value = 99
+		}
+		return node
+	})
+
+	if Equal(original, clone) {
+		t.Errorf("modifying the clone should make it differ from the original")
+	}
+	Inspect(original, func(n Node) bool {
+		if integer, ok := n.(*IntegerLiteral); ok && integer.Value == 99 {
+			t.Errorf("modifying the clone changed the original")
+		}
+		return true
+	})
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b     Node
+		expected bool
+	}{
+		{one(), one(), true},
+		{one(), two(), false},
+		{&Identifier{Value: "a"}, &Identifier{Value: "a"}, true},
+		{&Identifier{Value: "a"}, &StringLiteral{Value: "a"}, false},
+		{
+			&InfixExpression{Left: one(), Operator: "+", Right: two()},
+			&InfixExpression{Left: one(), Operator: "-", Right: two()},
+			false,
+		},
+		{
+			&AppealIfExpression{Condition: one(), Consequence: block(one())},
+			&AppealIfExpression{Condition: one(), Consequence: block(one())},
+			true,
+		},
+		{
+			&AppealIfExpression{Condition: one(), Consequence: block(one())},
+			&AppealIfExpression{Condition: one(), Consequence: block(one()), Alternative: block()},
+			false,
+		},
+		{
+			&FieldLiteral{Parameters: []Expression{&Identifier{Value: "a"}}, Body: block()},
+			&FieldLiteral{Parameters: []Expression{&Identifier{Value: "b"}}, Body: block()},
+			false,
+		},
+		{
+			&CallExpression{Function: &Identifier{Value: "f"}, Arguments: []Expression{one()}},
+			&CallExpression{Function: &Identifier{Value: "f"}, Arguments: []Expression{one(), two()}},
+			false,
+		},
+	}
+
+	for i, tt := range tests {
+		if got := Equal(tt.a, tt.b); got != tt.expected {
+			t.Errorf("test %d: Equal(%s, %s) wrong. want=%t, got=%t", i, tt.a, tt.b, tt.expected, got)
+		}
+	}
+}
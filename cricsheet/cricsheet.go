@@ -0,0 +1,433 @@
+// Package cricsheet reads Cricsheet's JSON match files (data version 1.x),
+// the open ball-by-ball format published at cricsheet.org, and summarises
+// each innings into batting and bowling figures.
+package cricsheet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type Match struct {
+	Version string
+	Info    Info
+	Innings []Innings
+}
+
+type Info struct {
+	Teams         []string
+	Dates         []string
+	Venue         string
+	City          string
+	MatchType     string
+	Gender        string
+	Overs         int
+	Event         string
+	MatchNumber   int
+	Toss          Toss
+	Outcome       Outcome
+	PlayerOfMatch []string
+	Players       map[string][]string
+	Registry      map[string]string // player name to Cricsheet person id
+}
+
+type Toss struct {
+	Winner   string
+	Decision string
+}
+
+// Outcome has a Winner and a margin in Runs or Wickets, or a Result such
+// as "tie", "draw" or "no result".
+type Outcome struct {
+	Winner     string
+	Runs       int
+	Wickets    int
+	Innings    int // set for an innings victory
+	Result     string
+	Method     string // "D/L" and the like
+	Eliminator string // winner of a super over or bowl-out
+}
+
+type Innings struct {
+	Team       string
+	Overs      []Over
+	Target     *Target
+	SuperOver  bool
+	Declared   bool
+	Forfeited  bool
+	PenaltyPre int // penalty runs awarded before the innings began
+}
+
+type Target struct {
+	Runs  int
+	Overs float64
+}
+
+type Over struct {
+	Number     int
+	Deliveries []Delivery
+}
+
+type Delivery struct {
+	Batter      string
+	Bowler      string
+	NonStriker  string
+	BatterRuns  int
+	ExtraRuns   int
+	TotalRuns   int
+	NonBoundary bool
+	Extras      Extras
+	Wickets     []Wicket
+}
+
+type Extras struct {
+	Wides   int
+	NoBalls int
+	Byes    int
+	LegByes int
+	Penalty int
+}
+
+type Wicket struct {
+	PlayerOut string
+	Kind      string
+	Fielders  []string
+}
+
+// Legal reports whether the delivery counts towards the over.
+func (d Delivery) Legal() bool { return d.Extras.Wides == 0 && d.Extras.NoBalls == 0 }
+
+// BowlerRuns are the runs charged to the bowler: all but byes, leg byes
+// and penalties.
+func (d Delivery) BowlerRuns() int {
+	return d.TotalRuns - d.Extras.Byes - d.Extras.LegByes - d.Extras.Penalty
+}
+
+// bowlerWickets are the dismissals credited to the bowler.
+var bowlerWickets = map[string]bool{
+	"bowled":            true,
+	"caught":            true,
+	"caught and bowled": true,
+	"lbw":               true,
+	"stumped":           true,
+	"hit wicket":        true,
+}
+
+// notDismissals end a stay at the crease without costing a wicket.
+var notDismissals = map[string]bool{
+	"retired hurt":    true,
+	"retired not out": true,
+}
+
+func Load(path string) (*Match, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+func Parse(data []byte) (*Match, error) {
+	var raw rawMatch
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("not a Cricsheet JSON file: %s", err)
+	}
+	if !strings.HasPrefix(raw.Meta.DataVersion, "1.") {
+		return nil, fmt.Errorf("unsupported Cricsheet data version %q, want 1.x", raw.Meta.DataVersion)
+	}
+
+	m := &Match{Version: raw.Meta.DataVersion}
+	info := raw.Info
+	m.Info = Info{
+		Teams:         info.Teams,
+		Dates:         info.Dates,
+		Venue:         info.Venue,
+		City:          info.City,
+		MatchType:     info.MatchType,
+		Gender:        info.Gender,
+		Overs:         info.Overs,
+		Event:         info.Event.Name,
+		MatchNumber:   info.Event.MatchNumber,
+		Toss:          Toss{Winner: info.Toss.Winner, Decision: info.Toss.Decision},
+		PlayerOfMatch: info.PlayerOfMatch,
+		Players:       info.Players,
+		Registry:      info.Registry.People,
+		Outcome: Outcome{
+			Winner:     info.Outcome.Winner,
+			Runs:       info.Outcome.By.Runs,
+			Wickets:    info.Outcome.By.Wickets,
+			Innings:    info.Outcome.By.Innings,
+			Result:     info.Outcome.Result,
+			Method:     info.Outcome.Method,
+			Eliminator: info.Outcome.Eliminator,
+		},
+	}
+
+	for i, rawInnings := range raw.Innings {
+		if rawInnings.Team == "" {
+			return nil, fmt.Errorf("innings %d has no team", i+1)
+		}
+		inn := Innings{
+			Team:      rawInnings.Team,
+			SuperOver: rawInnings.SuperOver,
+			Declared:  rawInnings.Declared,
+			Forfeited: rawInnings.Forfeited,
+		}
+		if rawInnings.Target != nil {
+			inn.Target = &Target{Runs: rawInnings.Target.Runs, Overs: rawInnings.Target.Overs}
+		}
+		if rawInnings.PenaltyRuns != nil {
+			inn.PenaltyPre = rawInnings.PenaltyRuns.Pre
+		}
+
+		for _, rawOver := range rawInnings.Overs {
+			over := Over{Number: rawOver.Over}
+			for _, d := range rawOver.Deliveries {
+				delivery := Delivery{
+					Batter:      d.Batter,
+					Bowler:      d.Bowler,
+					NonStriker:  d.NonStriker,
+					BatterRuns:  d.Runs.Batter,
+					ExtraRuns:   d.Runs.Extras,
+					TotalRuns:   d.Runs.Total,
+					NonBoundary: d.Runs.NonBoundary,
+					Extras: Extras{
+						Wides:   d.Extras.Wides,
+						NoBalls: d.Extras.NoBalls,
+						Byes:    d.Extras.Byes,
+						LegByes: d.Extras.LegByes,
+						Penalty: d.Extras.Penalty,
+					},
+				}
+				for _, w := range d.Wickets {
+					wicket := Wicket{PlayerOut: w.PlayerOut, Kind: w.Kind}
+					for _, f := range w.Fielders {
+						wicket.Fielders = append(wicket.Fielders, f.Name)
+					}
+					delivery.Wickets = append(delivery.Wickets, wicket)
+				}
+				over.Deliveries = append(over.Deliveries, delivery)
+			}
+			inn.Overs = append(inn.Overs, over)
+		}
+		m.Innings = append(m.Innings, inn)
+	}
+	return m, nil
+}
+
+// Totals adds up an innings: runs, wickets and legal balls bowled.
+func (inn *Innings) Totals() (runs, wickets, balls int) {
+	runs = inn.PenaltyPre
+	for _, over := range inn.Overs {
+		for _, d := range over.Deliveries {
+			runs += d.TotalRuns
+			if d.Legal() {
+				balls++
+			}
+			for _, w := range d.Wickets {
+				if !notDismissals[w.Kind] {
+					wickets++
+				}
+			}
+		}
+	}
+	return runs, wickets, balls
+}
+
+// ExtrasTotal adds up the extras conceded in an innings by kind.
+func (inn *Innings) ExtrasTotal() Extras {
+	var e Extras
+	for _, over := range inn.Overs {
+		for _, d := range over.Deliveries {
+			e.Wides += d.Extras.Wides
+			e.NoBalls += d.Extras.NoBalls
+			e.Byes += d.Extras.Byes
+			e.LegByes += d.Extras.LegByes
+			e.Penalty += d.Extras.Penalty
+		}
+	}
+	return e
+}
+
+type BattingFigures struct {
+	Name      string
+	Runs      int
+	Balls     int
+	Fours     int
+	Sixes     int
+	Dismissal string // empty when not out
+	Fielders  []string
+	Bowler    string // set when the bowler is credited with the wicket
+}
+
+// Out reports whether the batter lost their wicket; retiring hurt doesn't
+// count.
+func (b *BattingFigures) Out() bool {
+	return b.Dismissal != "" && !notDismissals[b.Dismissal]
+}
+
+type BowlingFigures struct {
+	Name    string
+	Balls   int
+	Runs    int
+	Wickets int
+	Wides   int
+	NoBalls int
+}
+
+// Batting lists every batter who came to the crease, in order of arrival.
+func (inn *Innings) Batting() []*BattingFigures {
+	var order []*BattingFigures
+	byName := map[string]*BattingFigures{}
+	batter := func(name string) *BattingFigures {
+		if b, ok := byName[name]; ok {
+			return b
+		}
+		b := &BattingFigures{Name: name}
+		byName[name] = b
+		order = append(order, b)
+		return b
+	}
+
+	for _, over := range inn.Overs {
+		for _, d := range over.Deliveries {
+			striker := batter(d.Batter)
+			batter(d.NonStriker)
+
+			striker.Runs += d.BatterRuns
+			if d.Extras.Wides == 0 {
+				striker.Balls++
+			}
+			if !d.NonBoundary {
+				switch d.BatterRuns {
+				case 4:
+					striker.Fours++
+				case 6:
+					striker.Sixes++
+				}
+			}
+			for _, w := range d.Wickets {
+				out := batter(w.PlayerOut)
+				out.Dismissal = w.Kind
+				out.Fielders = w.Fielders
+				if bowlerWickets[w.Kind] {
+					out.Bowler = d.Bowler
+				}
+			}
+		}
+	}
+	return order
+}
+
+// Bowling lists every bowler in the order they came on.
+func (inn *Innings) Bowling() []*BowlingFigures {
+	var order []*BowlingFigures
+	byName := map[string]*BowlingFigures{}
+
+	for _, over := range inn.Overs {
+		for _, d := range over.Deliveries {
+			b, ok := byName[d.Bowler]
+			if !ok {
+				b = &BowlingFigures{Name: d.Bowler}
+				byName[d.Bowler] = b
+				order = append(order, b)
+			}
+			if d.Legal() {
+				b.Balls++
+			}
+			b.Runs += d.BowlerRuns()
+			b.Wides += d.Extras.Wides
+			b.NoBalls += d.Extras.NoBalls
+			for _, w := range d.Wickets {
+				if bowlerWickets[w.Kind] {
+					b.Wickets++
+				}
+			}
+		}
+	}
+	return order
+}
+
+// The raw types mirror the JSON layout; Parse copies them into the
+// exported types above.
+type rawMatch struct {
+	Meta struct {
+		DataVersion string `json:"data_version"`
+	} `json:"meta"`
+	Info struct {
+		Teams     []string `json:"teams"`
+		Dates     []string `json:"dates"`
+		Venue     string   `json:"venue"`
+		City      string   `json:"city"`
+		MatchType string   `json:"match_type"`
+		Gender    string   `json:"gender"`
+		Overs     int      `json:"overs"`
+		Event     struct {
+			Name        string `json:"name"`
+			MatchNumber int    `json:"match_number"`
+		} `json:"event"`
+		Toss struct {
+			Winner   string `json:"winner"`
+			Decision string `json:"decision"`
+		} `json:"toss"`
+		Outcome struct {
+			Winner string `json:"winner"`
+			By     struct {
+				Runs    int `json:"runs"`
+				Wickets int `json:"wickets"`
+				Innings int `json:"innings"`
+			} `json:"by"`
+			Result     string `json:"result"`
+			Method     string `json:"method"`
+			Eliminator string `json:"eliminator"`
+		} `json:"outcome"`
+		PlayerOfMatch []string            `json:"player_of_match"`
+		Players       map[string][]string `json:"players"`
+		Registry      struct {
+			People map[string]string `json:"people"`
+		} `json:"registry"`
+	} `json:"info"`
+	Innings []struct {
+		Team      string `json:"team"`
+		SuperOver bool   `json:"super_over"`
+		Declared  bool   `json:"declared"`
+		Forfeited bool   `json:"forfeited"`
+		Target    *struct {
+			Runs  int     `json:"runs"`
+			Overs float64 `json:"overs"`
+		} `json:"target"`
+		PenaltyRuns *struct {
+			Pre int `json:"pre"`
+		} `json:"penalty_runs"`
+		Overs []struct {
+			Over       int `json:"over"`
+			Deliveries []struct {
+				Batter     string `json:"batter"`
+				Bowler     string `json:"bowler"`
+				NonStriker string `json:"non_striker"`
+				Runs       struct {
+					Batter      int  `json:"batter"`
+					Extras      int  `json:"extras"`
+					Total       int  `json:"total"`
+					NonBoundary bool `json:"non_boundary"`
+				} `json:"runs"`
+				Extras struct {
+					Wides   int `json:"wides"`
+					NoBalls int `json:"noballs"`
+					Byes    int `json:"byes"`
+					LegByes int `json:"legbyes"`
+					Penalty int `json:"penalty"`
+				} `json:"extras"`
+				Wickets []struct {
+					PlayerOut string `json:"player_out"`
+					Kind      string `json:"kind"`
+					Fielders  []struct {
+						Name string `json:"name"`
+					} `json:"fielders"`
+				} `json:"wickets"`
+			} `json:"deliveries"`
+		} `json:"overs"`
+	} `json:"innings"`
+}
@@ -0,0 +1,124 @@
+package cricsheet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func loadTestMatch(t *testing.T) *Match {
+	t.Helper()
+	m, err := Load("testdata/match.json")
+	if err != nil {
+		t.Fatalf("Load returned error: %s", err)
+	}
+	return m
+}
+
+func TestParseInfo(t *testing.T) {
+	m := loadTestMatch(t)
+	info := m.Info
+
+	if m.Version != "1.1.0" {
+		t.Errorf("wrong version. got=%q", m.Version)
+	}
+	if !reflect.DeepEqual(info.Teams, []string{"India", "Australia"}) {
+		t.Errorf("wrong teams. got=%v", info.Teams)
+	}
+	if info.MatchType != "ODI" || info.Overs != 50 || info.Event != "ICC Cricket World Cup" || info.MatchNumber != 48 {
+		t.Errorf("wrong match details. got=%+v", info)
+	}
+	if info.Toss != (Toss{Winner: "Australia", Decision: "field"}) {
+		t.Errorf("wrong toss. got=%+v", info.Toss)
+	}
+	if info.Outcome != (Outcome{Winner: "Australia", Wickets: 9}) {
+		t.Errorf("wrong outcome. got=%+v", info.Outcome)
+	}
+	if info.Registry["TM Head"] != "12b610c2" || len(info.Players["Australia"]) != 4 {
+		t.Errorf("wrong registry or players. got=%v %v", info.Registry, info.Players)
+	}
+}
+
+func TestInningsTotals(t *testing.T) {
+	m := loadTestMatch(t)
+
+	runs, wickets, balls := m.Innings[0].Totals()
+	if runs != 19 || wickets != 3 || balls != 8 {
+		t.Errorf("wrong totals. want 19/3 off 8 balls, got %d/%d off %d balls", runs, wickets, balls)
+	}
+	extras := m.Innings[0].ExtrasTotal()
+	if extras != (Extras{Wides: 1, NoBalls: 1, LegByes: 1}) {
+		t.Errorf("wrong extras. got=%+v", extras)
+	}
+	if target := m.Innings[1].Target; target == nil || target.Runs != 24 || target.Overs != 19.4 {
+		t.Errorf("wrong target. got=%+v", target)
+	}
+}
+
+func TestBatting(t *testing.T) {
+	batting := loadTestMatch(t).Innings[0].Batting()
+
+	want := []BattingFigures{
+		{Name: "RG Sharma", Runs: 9, Balls: 4, Fours: 1, Dismissal: "retired hurt"},
+		{Name: "Shubman Gill", Balls: 1, Dismissal: "caught", Fielders: []string{"A Zampa"}, Bowler: "MA Starc"},
+		{Name: "V Kohli", Runs: 7, Balls: 4, Sixes: 1, Dismissal: "caught and bowled", Bowler: "JR Hazlewood"},
+		{Name: "KL Rahul", Dismissal: "run out", Fielders: []string{"M Labuschagne", "JP Inglis"}},
+		{Name: "SS Iyer"},
+	}
+	if len(batting) != len(want) {
+		t.Fatalf("wrong number of batters. want=%d, got=%d", len(want), len(batting))
+	}
+	for i, b := range batting {
+		if !reflect.DeepEqual(*b, want[i]) {
+			t.Errorf("batter %d wrong.\nwant=%+v\ngot= %+v", i, want[i], *b)
+		}
+	}
+	if batting[0].Out() || !batting[3].Out() || batting[4].Out() {
+		t.Errorf("wrong Out: retired hurt and not out batters aren't out, run outs are")
+	}
+}
+
+func TestBowling(t *testing.T) {
+	m := loadTestMatch(t)
+
+	tests := []struct {
+		innings int
+		want    []BowlingFigures
+	}{
+		{0, []BowlingFigures{
+			{Name: "MA Starc", Balls: 6, Runs: 17, Wickets: 1, Wides: 1, NoBalls: 1},
+			{Name: "JR Hazlewood", Balls: 2, Runs: 1, Wickets: 1},
+		}},
+		{1, []BowlingFigures{{Name: "JJ Bumrah", Balls: 2, Runs: 6}}},
+	}
+
+	for _, tt := range tests {
+		bowling := m.Innings[tt.innings].Bowling()
+		if len(bowling) != len(tt.want) {
+			t.Fatalf("innings %d: wrong number of bowlers. got=%d", tt.innings, len(bowling))
+		}
+		for i, b := range bowling {
+			if *b != tt.want[i] {
+				t.Errorf("innings %d bowler %d wrong.\nwant=%+v\ngot= %+v", tt.innings, i, tt.want[i], *b)
+			}
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`[1, 2]`, "not a Cricsheet JSON file: json: cannot unmarshal array into Go value of type cricsheet.rawMatch"},
+		{`{"meta": {"data_version": "0.9"}}`, `unsupported Cricsheet data version "0.9", want 1.x`},
+		{`{"info": {}}`, `unsupported Cricsheet data version "", want 1.x`},
+		{`{"meta": {"data_version": "1.0.0"}, "innings": [{"overs": []}]}`, "innings 1 has no team"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.input))
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Parse(%s) wrong error. want=%q, got=%v", tt.input, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package dls
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// standardEdition is a sample of the published Standard Edition table for a
+// 50 over innings: resources by overs left and wickets down, 0 to 9.
+var standardEdition = map[int64][10]float64{
+	50: {100.0, 93.4, 85.1, 74.9, 62.7, 49.0, 34.9, 22.0, 11.9, 4.7},
+	40: {89.3, 84.2, 77.8, 69.6, 59.5, 47.6, 34.6, 22.0, 11.9, 4.7},
+	30: {75.1, 71.8, 67.3, 61.6, 54.1, 44.7, 33.6, 21.8, 11.9, 4.7},
+	25: {66.5, 63.9, 60.5, 56.0, 50.0, 42.2, 32.6, 21.6, 11.9, 4.7},
+	20: {56.6, 54.8, 52.4, 49.1, 44.6, 38.6, 30.8, 21.2, 11.9, 4.7},
+	10: {32.1, 31.6, 30.8, 29.8, 28.3, 26.1, 22.8, 17.9, 11.4, 4.7},
+	5:  {17.2, 17.0, 16.8, 16.5, 16.1, 15.4, 14.3, 12.5, 9.4, 4.6},
+}
+
+func TestResourcesMatchStandardEdition(t *testing.T) {
+	for overs, row := range standardEdition {
+		for wickets, want := range row {
+			got, err := Resources(50, overs*6, wickets)
+			if err != nil {
+				t.Fatalf("Resources(50, %d, %d) returned error: %s", overs*6, wickets, err)
+			}
+			if math.Abs(got-want) > 0.1+1e-9 {
+				t.Errorf("%d overs left, %d down: want %v (published), got %v", overs, wickets, want, got)
+			}
+		}
+	}
+}
+
+func TestResources(t *testing.T) {
+	tests := []struct {
+		overs   int64
+		balls   int64
+		wickets int
+		want    float64
+	}{
+		{50, 300, 0, 100},
+		{50, 240, 0, 89.3},
+		{50, 120, 0, 56.6},
+		{50, 60, 0, 32.2},
+		{50, 0, 0, 0},
+		{50, 300, 5, 49.0},
+		{50, 300, 8, 11.9},
+		{50, 120, 5, 38.7},
+		{50, 120, 8, 11.9},
+		{50, 120, 10, 0},
+		{20, 120, 0, 100},
+		{20, 60, 0, 56.8},
+	}
+
+	for _, tt := range tests {
+		got, err := Resources(tt.overs, tt.balls, tt.wickets)
+		if err != nil {
+			t.Fatalf("Resources(%d, %d, %d) returned error: %s", tt.overs, tt.balls, tt.wickets, err)
+		}
+		if got != tt.want {
+			t.Errorf("Resources(%d, %d, %d) wrong. want=%v, got=%v", tt.overs, tt.balls, tt.wickets, tt.want, got)
+		}
+	}
+}
+
+func TestResourcesFallWithWickets(t *testing.T) {
+	for _, overs := range []int64{50, 20} {
+		for balls := int64(1); balls <= overs*6; balls++ {
+			prev := 101.0
+			for wickets := 0; wickets <= 10; wickets++ {
+				got, _ := Resources(overs, balls, wickets)
+				if got > prev {
+					t.Fatalf("%d overs, %d balls left: resources rise from %v to %v at %d down", overs, balls, prev, got, wickets)
+				}
+				prev = got
+			}
+		}
+	}
+}
+
+func TestResourcesErrors(t *testing.T) {
+	tests := []struct {
+		overs   int64
+		balls   int64
+		wickets int
+		want    string
+	}{
+		{40, 240, 0, "DLS supports 50 and 20 over matches, got 40"},
+		{50, 301, 0, "50.1 overs left is outside a 50 over innings"},
+		{50, 60, 11, "wickets must be 0 to 10, got 11"},
+	}
+
+	for _, tt := range tests {
+		_, err := Resources(tt.overs, tt.balls, tt.wickets)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Resources(%d, %d, %d) wrong error. want=%q, got=%v", tt.overs, tt.balls, tt.wickets, tt.want, err)
+		}
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name   string
+		match  Match
+		target int64
+		r1, r2 float64
+	}{
+		{"no stoppages", Match{Overs: 50, Team1Score: 250}, 251, 100, 100},
+		{
+			"first innings cut short",
+			Match{Overs: 50, Team1Score: 250, Stoppages: []Stoppage{{Innings: 1, Balls: 180, Wickets: 3, Score: 150, OversLost: 10}}},
+			272, 80.7, 89.3,
+		},
+		{
+			"chase cut short",
+			Match{Overs: 50, Team1Score: 250, Stoppages: []Stoppage{{Innings: 2, Balls: 120, Wickets: 2, Score: 100, OversLost: 20}}},
+			159, 100, 63.5,
+		},
+		{
+			"custom G",
+			Match{Overs: 50, Team1Score: 250, G: 300, Stoppages: []Stoppage{{Innings: 1, Balls: 180, Wickets: 3, OversLost: 10}}},
+			276, 80.7, 89.3,
+		},
+		{
+			"twenty overs",
+			Match{Overs: 20, Team1Score: 180, Stoppages: []Stoppage{{Innings: 2, Balls: 0, OversLost: 8}}},
+			120, 100, 66.4,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := Calculate(tt.match)
+		if err != nil {
+			t.Fatalf("%s: Calculate returned error: %s", tt.name, err)
+		}
+		if result.Target != tt.target || result.Team1Resources != tt.r1 || result.Team2Resources != tt.r2 {
+			t.Errorf("%s: wrong result. want target=%d r1=%v r2=%v, got target=%d r1=%v r2=%v",
+				tt.name, tt.target, tt.r1, tt.r2, result.Target, result.Team1Resources, result.Team2Resources)
+		}
+	}
+}
+
+func TestCalculateErrors(t *testing.T) {
+	tests := []struct {
+		match Match
+		want  string
+	}{
+		{Match{Overs: 30, Team1Score: 200}, "DLS supports 50 and 20 over matches, got 30"},
+		{Match{Overs: 50, Team1Score: -1}, "team 1 score can't be negative, got -1"},
+		{Match{Overs: 50, Stoppages: []Stoppage{{Innings: 3, OversLost: 1}}}, "stoppage innings must be 1 or 2, got 3"},
+		{Match{Overs: 50, Stoppages: []Stoppage{{Innings: 1, Balls: 280, OversLost: 5}}}, "stoppage in innings 1 at 46.4 overs: 5 overs lost but only 3.2 remain"},
+		{Match{Overs: 50, Stoppages: []Stoppage{{Innings: 1, Balls: 60}}}, "stoppage in innings 1 at 10.0 overs: overs lost must be positive, got 0"},
+		{Match{Overs: 20, Stoppages: []Stoppage{{Innings: 2, OversLost: 20}}}, "team 2 has no resources left"},
+	}
+
+	for _, tt := range tests {
+		_, err := Calculate(tt.match)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("wrong error. want=%q, got=%v", tt.want, err)
+		}
+	}
+}
+
+func TestPar(t *testing.T) {
+	result, err := Calculate(Match{Overs: 50, Team1Score: 250, Stoppages: []Stoppage{{Innings: 2, Balls: 120, Wickets: 2, OversLost: 20}}})
+	if err != nil {
+		t.Fatalf("Calculate returned error: %s", err)
+	}
+
+	tests := []struct {
+		balls   int64
+		wickets int
+		want    int64
+	}{
+		{0, 0, 0},
+		{120, 2, 81},
+		{150, 3, 117},
+		{180, 0, result.Target - 1},
+	}
+	for _, tt := range tests {
+		par, err := result.Par(tt.balls, tt.wickets)
+		if err != nil {
+			t.Fatalf("Par(%d, %d) returned error: %s", tt.balls, tt.wickets, err)
+		}
+		if par != tt.want {
+			t.Errorf("Par(%d, %d) wrong. want=%d, got=%d", tt.balls, tt.wickets, tt.want, par)
+		}
+	}
+
+	if _, err := result.Par(181, 0); err == nil || err.Error() != "team 2 only has 30.0 overs" {
+		t.Errorf("Par past the allocation wrong error. got=%v", err)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	result, _ := Calculate(Match{Overs: 50, Team1Score: 250, Stoppages: []Stoppage{{Innings: 1, Balls: 180, Wickets: 3, Score: 150, OversLost: 10}}})
+	explanation := strings.Join(result.Explain(), "\n")
+
+	for _, want := range []string{
+		"innings 1 stopped at 150/3 after 30.0 overs: 10 overs lost, 20.0 to 10.0 overs left with 3 down, 49.1% to 29.8%, 19.3% lost",
+		"team 1 made 250 in 40.0 overs using 80.7% of resources",
+		"the target is 250 + 245.0 x (89.3 - 80.7) / 100 + 1 = 272",
+	} {
+		if !strings.Contains(explanation, want) {
+			t.Errorf("explanation is missing %q. got=\n%s", want, explanation)
+		}
+	}
+}
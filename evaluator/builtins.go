@@ -9,7 +9,7 @@ import (
 
 var builtins = map[string]*object.Builtin{
 	"thala": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return newMisfield("girlfriend se raat mei baat kar lena, pehle %d ki jagah 1 argument daal de", len(args))
 			}
@@ -24,16 +24,16 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"gambhir": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
 			if len(args) != 2 {
 				return newMisfield("*Gautam Gambhir Stares Angrily* got=%d arguments, want=2 arguments", len(args))
 			}
-			fmt.Printf("Interviewer: %v or %v\n", args[0].Inspect(), args[1].Inspect())
-			return returnRandomValue()
+			fmt.Fprintf(ctx.Env.Runtime().Out, "Interviewer: %v or %v\n", args[0].Inspect(), args[1].Inspect())
+			return returnRandomValue(ctx.Env.Runtime().Rand)
 		},
 	},
 	"kohli": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
 			message := "shaam tak khelenge, inki G phatt jaayegi lekin abhi tera code phatt gaya"
 			for _, arg := range args {
 				message += " " + arg.Inspect()
@@ -43,7 +43,7 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"rohit": &object.Builtin{
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return newMisfield("mera gale ka vaat lag gaya chilla chilla ke ki 1 argument chahiye! tunne %d de diye", len(args))
 			}
@@ -51,12 +51,39 @@ var builtins = map[string]*object.Builtin{
 			if !ok {
 				return newMisfield("mera gale ka vaat lag gaya chilla chilla ke ki sahi type ka argument daal de")
 			}
-			fmt.Printf("Reporter: %s ke birthday ke baare mei kuch boliye.\n", arg.Value)
+			fmt.Fprintf(ctx.Env.Runtime().Out, "Reporter: %s ke birthday ke baare mei kuch boliye.\n", arg.Value)
 			return &object.String{Value: "Rohit: Abhi birthday mei kya bola jata hai? Happy Birthday? Yahi bola jata hai."}
 		},
 	},
 }
 
+func init() {
+	registerBuiltins(arrayBuiltins)
+	registerBuiltins(higherOrderBuiltins)
+	registerBuiltins(stringBuiltins)
+	registerBuiltins(outputBuiltins)
+	registerBuiltins(mathBuiltins)
+	registerBuiltins(randomBuiltins)
+	registerBuiltins(fileBuiltins)
+	registerBuiltins(hashBuiltins)
+	registerBuiltins(jsonBuiltins)
+	registerBuiltins(inningsBuiltins)
+	registerBuiltins(statsBuiltins)
+	registerBuiltins(dlsBuiltins)
+	registerBuiltins(simBuiltins)
+	registerBuiltins(csvBuiltins)
+	registerBuiltins(cricsheetBuiltins)
+	registerMethods()
+}
+
+// registerBuiltins adds a group of builtins kept in its own file to the
+// builtins every program can see.
+func registerBuiltins(group map[string]*object.Builtin) {
+	for name, builtin := range group {
+		builtins[name] = builtin
+	}
+}
+
 func calculateLength(arg object.Object) string {
 	len := int(len(arg.(*object.String).Value))
 	if len == 7 {
@@ -88,7 +115,7 @@ func findDigitSum(num int) int {
 	return res
 }
 
-func returnRandomValue() object.Object {
+func returnRandomValue(rng *rand.Rand) object.Object {
 	options := []object.Object{
 		&object.String{Value: "Gautam Gambhir: baingan"},
 		&object.String{Value: "Gautam Gambhir: shaktimaan"},
@@ -96,7 +123,7 @@ func returnRandomValue() object.Object {
 		&object.String{Value: "Gautam Gambhir: 23"},
 		&object.String{Value: "Gautam Gambhir: spider-man"},
 	}
-	randomIndex := rand.Intn(len(options))
+	randomIndex := rng.Intn(len(options))
 	pick := options[randomIndex]
 	return pick
 }
@@ -0,0 +1,162 @@
+package evaluator
+
+import "CricLang/object"
+
+var arrayBuiltins = map[string]*object.Builtin{
+	"len": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newMisfield("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			switch arg := args[0].(type) {
+			case *object.Array:
+				return &object.Integer{Value: int64(len(arg.Elements))}
+			case *object.String:
+				return &object.Integer{Value: stringLength(arg.Value)}
+			case *object.Hash:
+				return &object.Integer{Value: int64(len(arg.Keys))}
+			default:
+				return newMisfield("argument to `len` not supported, got %s", args[0].Type())
+			}
+		},
+	},
+	"first": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			arr, misfield := arrayArgument("first", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			if len(arr.Elements) == 0 {
+				return DEAD_BALL
+			}
+			return arr.Elements[0]
+		},
+	},
+	"last": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			arr, misfield := arrayArgument("last", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			if len(arr.Elements) == 0 {
+				return DEAD_BALL
+			}
+			return arr.Elements[len(arr.Elements)-1]
+		},
+	},
+	"rest": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			arr, misfield := arrayArgument("rest", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			if len(arr.Elements) == 0 {
+				return DEAD_BALL
+			}
+			return newArray(arr.Elements[1:])
+		},
+	},
+	"push": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			arr, misfield := arrayArgument("push", 2, args)
+			if misfield != nil {
+				return misfield
+			}
+			elements := make([]object.Object, len(arr.Elements), len(arr.Elements)+1)
+			copy(elements, arr.Elements)
+			return &object.Array{Elements: append(elements, args[1])}
+		},
+	},
+	"concat": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) == 0 {
+				return newMisfield("wrong number of arguments. got=0, want at least 1")
+			}
+			elements := []object.Object{}
+			for _, arg := range args {
+				arr, ok := arg.(*object.Array)
+				if !ok {
+					return newMisfield("argument to `concat` must be ARRAY, got %s", arg.Type())
+				}
+				elements = append(elements, arr.Elements...)
+			}
+			return &object.Array{Elements: elements}
+		},
+	},
+	"slice": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) != 2 && len(args) != 3 {
+				return newMisfield("wrong number of arguments. got=%d, want=2 or 3", len(args))
+			}
+			arr, ok := args[0].(*object.Array)
+			if !ok {
+				return newMisfield("argument to `slice` must be ARRAY, got %s", args[0].Type())
+			}
+			length := int64(len(arr.Elements))
+
+			bounds := []int64{0, length}
+			for i, arg := range args[1:] {
+				integer, ok := arg.(*object.Integer)
+				if !ok {
+					return newMisfield("bounds of `slice` must be INTEGER, got %s", arg.Type())
+				}
+				bounds[i] = clampIndex(integer.Value, length)
+			}
+
+			start, end := bounds[0], bounds[1]
+			if start > end {
+				start = end
+			}
+			return newArray(arr.Elements[start:end])
+		},
+	},
+	"reverse": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			arr, misfield := arrayArgument("reverse", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			length := len(arr.Elements)
+			elements := make([]object.Object, length)
+			for i, e := range arr.Elements {
+				elements[length-1-i] = e
+			}
+			return &object.Array{Elements: elements}
+		},
+	},
+}
+
+// arrayArgument checks a builtin got want arguments, the first an array.
+func arrayArgument(name string, want int, args []object.Object) (*object.Array, *object.Misfield) {
+	if len(args) != want {
+		return nil, newMisfield("wrong number of arguments. got=%d, want=%d", len(args), want)
+	}
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return nil, newMisfield("argument to `%s` must be ARRAY, got %s", name, args[0].Type())
+	}
+	return arr, nil
+}
+
+// newArray copies elements so the result never shares storage with the
+// array it was cut from.
+func newArray(elements []object.Object) *object.Array {
+	copied := make([]object.Object, len(elements))
+	copy(copied, elements)
+	return &object.Array{Elements: copied}
+}
+
+// clampIndex turns a possibly negative index, counted from the end, into
+// one within [0, length].
+func clampIndex(index, length int64) int64 {
+	if index < 0 {
+		index += length
+	}
+	if index < 0 {
+		return 0
+	}
+	if index > length {
+		return length
+	}
+	return index
+}
@@ -0,0 +1,102 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"testing"
+)
+
+func TestArrayBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`len([])`, 0},
+		{`len([1, 2, 3])`, 3},
+		{`len([1, 2, 3]) * 2`, 6},
+		{`len(1)`, "argument to `len` not supported, got INTEGER"},
+		{`len([1], [2])`, "wrong number of arguments. got=2, want=1"},
+		{`first([1, 2, 3])`, 1},
+		{`first([])`, nil},
+		{`first(1)`, "argument to `first` must be ARRAY, got INTEGER"},
+		{`last([1, 2, 3])`, 3},
+		{`last([])`, nil},
+		{`rest([1, 2, 3])`, []int{2, 3}},
+		{`rest([1])`, []int{}},
+		{`rest([])`, nil},
+		{`push([], 1)`, []int{1}},
+		{`push([1, 2], 3)`, []int{1, 2, 3}},
+		{`push(1, 1)`, "argument to `push` must be ARRAY, got INTEGER"},
+		{`push([1])`, "wrong number of arguments. got=1, want=2"},
+		{`concat([1], [], [2, 3])`, []int{1, 2, 3}},
+		{`concat([1])`, []int{1}},
+		{`concat([1], 2)`, "argument to `concat` must be ARRAY, got INTEGER"},
+		{`concat()`, "wrong number of arguments. got=0, want at least 1"},
+		{`slice([1, 2, 3, 4], 1)`, []int{2, 3, 4}},
+		{`slice([1, 2, 3, 4], 1, 3)`, []int{2, 3}},
+		{`slice([1, 2, 3, 4], -2)`, []int{3, 4}},
+		{`slice([1, 2, 3, 4], 0, -1)`, []int{1, 2, 3}},
+		{`slice([1, 2, 3, 4], 3, 1)`, []int{}},
+		{`slice([1, 2, 3, 4], -10, 10)`, []int{1, 2, 3, 4}},
+		{`slice([1, 2], "1")`, "bounds of `slice` must be INTEGER, got STRING"},
+		{`slice([1, 2])`, "wrong number of arguments. got=1, want=2 or 3"},
+		{`reverse([1, 2, 3])`, []int{3, 2, 1}},
+		{`reverse([])`, []int{}},
+		{`thala([1, 2, 3])`, "Captain Cool: 3"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testBuiltinResult(t, tt.input, evaluated, tt.expected)
+	}
+}
+
+func TestArrayBuiltinsDoNotMutate(t *testing.T) {
+	input := `
+	player a = [1, 2, 3];
+	player b = push(a, 4);
+	player c = reverse(a);
+	player d = rest(a);
+	concat(a, b, c, d)
+	`
+
+	testBuiltinResult(t, input, testEval(input), []int{1, 2, 3, 1, 2, 3, 4, 3, 2, 1, 2, 3})
+}
+
+// testBuiltinResult checks a builtin's result: an int, a []int array, nil for
+// DEAD_BALL, or a string that is either a Misfield message or a STRING.
+func testBuiltinResult(t *testing.T, input string, evaluated object.Object, expected interface{}) {
+	t.Helper()
+
+	switch expected := expected.(type) {
+	case int:
+		testIntegerObject(t, evaluated, int64(expected))
+	case nil:
+		testNullObject(t, evaluated)
+	case []int:
+		array, ok := evaluated.(*object.Array)
+		if !ok {
+			t.Errorf("%s: object is not Array. got=%T (%+v)", input, evaluated, evaluated)
+			return
+		}
+		if len(array.Elements) != len(expected) {
+			t.Errorf("%s: wrong number of elements. want=%d, got=%d", input, len(expected), len(array.Elements))
+			return
+		}
+		for i, expectedElem := range expected {
+			testIntegerObject(t, array.Elements[i], int64(expectedElem))
+		}
+	case string:
+		switch evaluated := evaluated.(type) {
+		case *object.Misfield:
+			if evaluated.Message != expected {
+				t.Errorf("%s: wrong error message. expected=%q, got=%q", input, expected, evaluated.Message)
+			}
+		case *object.String:
+			if evaluated.Value != expected {
+				t.Errorf("%s: wrong string. expected=%q, got=%q", input, expected, evaluated.Value)
+			}
+		default:
+			t.Errorf("%s: object is not Misfield or String. got=%T (%+v)", input, evaluated, evaluated)
+		}
+	}
+}
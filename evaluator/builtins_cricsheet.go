@@ -0,0 +1,240 @@
+package evaluator
+
+import (
+	"CricLang/cricsheet"
+	"CricLang/object"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// loadCricsheet reads a Cricsheet JSON match file. Innings, batting and
+// bowling figures use the same keys as the Innings object and the stats
+// builtins: runs, balls, wickets and overs as "19.4".
+var cricsheetBuiltins = map[string]*object.Builtin{
+	"loadCricsheet": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			path, misfield := filePathArgument(ctx, "loadCricsheet", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			match, err := cricsheet.Load(path)
+			if err != nil {
+				if _, ok := err.(*os.PathError); ok {
+					return fileMisfield("loadCricsheet", err)
+				}
+				return newMisfield("loadCricsheet: %s", err)
+			}
+			return cricsheetMatchObject(match)
+		},
+	},
+}
+
+func cricsheetMatchObject(match *cricsheet.Match) object.Object {
+	info := match.Info
+	infoHash := object.NewHash()
+	infoHash.Set("teams", stringArray(info.Teams))
+	infoHash.Set("dates", stringArray(info.Dates))
+	infoHash.Set("venue", &object.String{Value: info.Venue})
+	infoHash.Set("city", &object.String{Value: info.City})
+	infoHash.Set("matchType", &object.String{Value: info.MatchType})
+	infoHash.Set("gender", &object.String{Value: info.Gender})
+	infoHash.Set("overs", &object.Integer{Value: int64(info.Overs)})
+	infoHash.Set("event", &object.String{Value: info.Event})
+	infoHash.Set("matchNumber", &object.Integer{Value: int64(info.MatchNumber)})
+
+	toss := object.NewHash()
+	toss.Set("winner", &object.String{Value: info.Toss.Winner})
+	toss.Set("decision", &object.String{Value: info.Toss.Decision})
+	infoHash.Set("toss", toss)
+
+	outcome := object.NewHash()
+	if info.Outcome.Winner != "" {
+		outcome.Set("winner", &object.String{Value: info.Outcome.Winner})
+	}
+	for _, by := range []struct {
+		key   string
+		value int
+	}{{"runs", info.Outcome.Runs}, {"wickets", info.Outcome.Wickets}, {"innings", info.Outcome.Innings}} {
+		if by.value != 0 {
+			outcome.Set(by.key, &object.Integer{Value: int64(by.value)})
+		}
+	}
+	for _, detail := range []struct{ key, value string }{
+		{"result", info.Outcome.Result},
+		{"method", info.Outcome.Method},
+		{"eliminator", info.Outcome.Eliminator},
+	} {
+		if detail.value != "" {
+			outcome.Set(detail.key, &object.String{Value: detail.value})
+		}
+	}
+	infoHash.Set("outcome", outcome)
+	infoHash.Set("playerOfMatch", stringArray(info.PlayerOfMatch))
+
+	players := object.NewHash()
+	for _, team := range info.Teams {
+		players.Set(team, stringArray(info.Players[team]))
+	}
+	infoHash.Set("players", players)
+
+	registry := object.NewHash()
+	for _, name := range sortedKeys(info.Registry) {
+		registry.Set(name, &object.String{Value: info.Registry[name]})
+	}
+
+	innings := make([]object.Object, len(match.Innings))
+	for i := range match.Innings {
+		innings[i] = cricsheetInningsObject(&match.Innings[i])
+	}
+
+	out := object.NewHash()
+	out.Set("version", &object.String{Value: match.Version})
+	out.Set("info", infoHash)
+	out.Set("registry", registry)
+	out.Set("innings", &object.Array{Elements: innings})
+	return out
+}
+
+func cricsheetInningsObject(inn *cricsheet.Innings) object.Object {
+	runs, wickets, balls := inn.Totals()
+	out := object.NewHash()
+	out.Set("team", &object.String{Value: inn.Team})
+	out.Set("runs", &object.Integer{Value: int64(runs)})
+	out.Set("wickets", &object.Integer{Value: int64(wickets)})
+	out.Set("balls", &object.Integer{Value: int64(balls)})
+	out.Set("overs", &object.String{Value: object.FormatOvers(int64(balls))})
+	if inn.Target != nil {
+		target := object.NewHash()
+		target.Set("runs", &object.Integer{Value: int64(inn.Target.Runs)})
+		target.Set("overs", &object.String{Value: oversFromFloat(inn.Target.Overs)})
+		out.Set("target", target)
+	}
+	out.Set("superOver", nativeBoolToBooleanObject(inn.SuperOver))
+
+	e := inn.ExtrasTotal()
+	extras := object.NewHash()
+	extras.Set("byes", &object.Integer{Value: int64(e.Byes)})
+	extras.Set("legByes", &object.Integer{Value: int64(e.LegByes)})
+	extras.Set("wides", &object.Integer{Value: int64(e.Wides)})
+	extras.Set("noBalls", &object.Integer{Value: int64(e.NoBalls)})
+	extras.Set("penalty", &object.Integer{Value: int64(e.Penalty + inn.PenaltyPre)})
+	extras.Set("total", &object.Integer{Value: int64(e.Byes + e.LegByes + e.Wides + e.NoBalls + e.Penalty + inn.PenaltyPre)})
+	out.Set("extras", extras)
+
+	batting := []object.Object{}
+	for _, b := range inn.Batting() {
+		card := object.NewHash()
+		card.Set("name", &object.String{Value: b.Name})
+		card.Set("runs", &object.Integer{Value: int64(b.Runs)})
+		card.Set("balls", &object.Integer{Value: int64(b.Balls)})
+		card.Set("fours", &object.Integer{Value: int64(b.Fours)})
+		card.Set("sixes", &object.Integer{Value: int64(b.Sixes)})
+		dismissal := b.Dismissal
+		if dismissal == "" {
+			dismissal = "not out"
+		}
+		card.Set("dismissal", &object.String{Value: dismissal})
+		card.Set("out", nativeBoolToBooleanObject(b.Out()))
+		batting = append(batting, card)
+	}
+	out.Set("batting", &object.Array{Elements: batting})
+
+	bowling := []object.Object{}
+	for _, b := range inn.Bowling() {
+		figures := object.NewHash()
+		figures.Set("name", &object.String{Value: b.Name})
+		figures.Set("balls", &object.Integer{Value: int64(b.Balls)})
+		figures.Set("overs", &object.String{Value: object.FormatOvers(int64(b.Balls))})
+		figures.Set("runs", &object.Integer{Value: int64(b.Runs)})
+		figures.Set("wickets", &object.Integer{Value: int64(b.Wickets)})
+		figures.Set("wides", &object.Integer{Value: int64(b.Wides)})
+		figures.Set("noBalls", &object.Integer{Value: int64(b.NoBalls)})
+		bowling = append(bowling, figures)
+	}
+	out.Set("bowling", &object.Array{Elements: bowling})
+
+	overs := make([]object.Object, len(inn.Overs))
+	for i, over := range inn.Overs {
+		deliveries := make([]object.Object, len(over.Deliveries))
+		legal, overRuns := 0, 0
+		for j, d := range over.Deliveries {
+			deliveries[j] = cricsheetDeliveryObject(over.Number, legal, d)
+			if d.Legal() {
+				legal++
+			}
+			overRuns += d.TotalRuns
+		}
+		overHash := object.NewHash()
+		overHash.Set("over", &object.Integer{Value: int64(over.Number)})
+		overHash.Set("runs", &object.Integer{Value: int64(overRuns)})
+		overHash.Set("deliveries", &object.Array{Elements: deliveries})
+		overs[i] = overHash
+	}
+	out.Set("byOver", &object.Array{Elements: overs})
+	return out
+}
+
+// cricsheetDeliveryObject labels a delivery "over.ball" like the simulation
+// logs; a wide or no-ball shares the label of the ball bowled after it.
+func cricsheetDeliveryObject(over, legal int, d cricsheet.Delivery) object.Object {
+	out := object.NewHash()
+	out.Set("ball", &object.String{Value: fmt.Sprintf("%d.%d", over, legal+1)})
+	out.Set("batter", &object.String{Value: d.Batter})
+	out.Set("bowler", &object.String{Value: d.Bowler})
+	out.Set("nonStriker", &object.String{Value: d.NonStriker})
+	out.Set("runs", &object.Integer{Value: int64(d.BatterRuns)})
+	out.Set("extras", &object.Integer{Value: int64(d.ExtraRuns)})
+	out.Set("total", &object.Integer{Value: int64(d.TotalRuns)})
+
+	kinds := object.NewHash()
+	for _, kind := range []struct {
+		key  string
+		runs int
+	}{
+		{"wides", d.Extras.Wides},
+		{"noBalls", d.Extras.NoBalls},
+		{"byes", d.Extras.Byes},
+		{"legByes", d.Extras.LegByes},
+		{"penalty", d.Extras.Penalty},
+	} {
+		if kind.runs != 0 {
+			kinds.Set(kind.key, &object.Integer{Value: int64(kind.runs)})
+		}
+	}
+	out.Set("extrasKind", kinds)
+
+	wickets := make([]object.Object, len(d.Wickets))
+	for i, w := range d.Wickets {
+		wicket := object.NewHash()
+		wicket.Set("playerOut", &object.String{Value: w.PlayerOut})
+		wicket.Set("kind", &object.String{Value: w.Kind})
+		wicket.Set("fielders", stringArray(w.Fielders))
+		wickets[i] = wicket
+	}
+	out.Set("wickets", &object.Array{Elements: wickets})
+	return out
+}
+
+// oversFromFloat reads Cricsheet's target overs, which it writes as a
+// number in overs notation: 19.4 is nineteen overs and four balls.
+func oversFromFloat(overs float64) string {
+	return object.FormatOvers(int64(overs)*object.BallsPerOver + int64((overs-float64(int64(overs)))*10+0.5))
+}
+
+func stringArray(values []string) *object.Array {
+	elements := make([]object.Object, len(values))
+	for i, v := range values {
+		elements[i] = &object.String{Value: v}
+	}
+	return &object.Array{Elements: elements}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,63 @@
+package evaluator
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLoadCricsheet(t *testing.T) {
+	match, err := os.ReadFile("../cricsheet/testdata/match.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := writeModules(t, map[string]string{
+		"final.json": string(match),
+		"old.json":   `{"meta": {"data_version": "0.9"}}`,
+	})
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`loadCricsheet("final.json")["info"]["venue"]`, "Narendra Modi Stadium"},
+		{`loadCricsheet("final.json")["info"]["outcome"]["wickets"]`, 9},
+		{`loadCricsheet("final.json")["info"]["toss"]["decision"]`, "field"},
+		{`loadCricsheet("final.json")["info"]["players"]["India"][2]`, "V Kohli"},
+		{`loadCricsheet("final.json")["registry"]["TM Head"]`, "12b610c2"},
+		{`keys(loadCricsheet("final.json")["registry"])[0]`, "DA Warner"},
+		{`loadCricsheet("final.json")["innings"][0]["runs"]`, 19},
+		{`loadCricsheet("final.json")["innings"][0]["overs"]`, "1.2"},
+		{`loadCricsheet("final.json")["innings"][0]["extras"]["total"]`, 3},
+		{`loadCricsheet("final.json")["innings"][1]["target"]["overs"]`, "19.4"},
+		{`loadCricsheet("final.json")["innings"][0]["batting"][2]["dismissal"]`, "caught and bowled"},
+		{`loadCricsheet("final.json")["innings"][0]["bowling"][0]["overs"]`, "1.0"},
+		{`loadCricsheet("final.json")["innings"][0]["byOver"][0]["deliveries"][4]["extrasKind"]["noBalls"]`, 1},
+		{`loadCricsheet("final.json")["innings"][0]["byOver"][1]["deliveries"][0]["wickets"][0]["fielders"][1]`, "JP Inglis"},
+		{`loadCricsheet("final.json")["innings"][0]["byOver"][0]["runs"]`, 18},
+		{`loadCricsheet("old.json")`, `loadCricsheet: unsupported Cricsheet data version "0.9", want 1.x`},
+		{`loadCricsheet("missing.json")`, "loadCricsheet: missing.json: no such file or directory"},
+		{`loadCricsheet("/etc/passwd")`, "loadCricsheet: /etc/passwd is outside the allowed directories"},
+	}
+
+	for _, tt := range tests {
+		testBuiltinResult(t, tt.input, testEvalWithFiles(tt.input, root, root), tt.expected)
+	}
+
+	// the figures feed straight into the stats builtins
+	inspected := []struct {
+		input    string
+		expected string
+	}{
+		{`player d = loadCricsheet("final.json")["innings"][0]["byOver"][0]["deliveries"]; [d[1]["ball"], d[2]["ball"]]`, "[0.2, 0.2]"},
+		{`player inn = loadCricsheet("final.json")["innings"][0]; runRate(inn["runs"], inn["overs"])`, "14.25"},
+		{`player b = loadCricsheet("final.json")["innings"][0]["bowling"][0]; economy(b["runs"], b["overs"])`, "17.0"},
+		{`player b = loadCricsheet("final.json")["innings"][0]["batting"][2]; strikeRate(b["runs"], b["balls"])`, "175.0"},
+	}
+
+	for _, tt := range inspected {
+		evaluated := testEvalWithFiles(tt.input, root, root)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: wrong value. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"bytes"
+	"encoding/csv"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// readCSV and parseCSV take an optional options hash:
+//
+//	{"header": notout}  the first row names the columns; rows become hashes
+//	{"infer": out}      keep every cell a string
+//
+// By default a column whose every cell is an integer reads as integers.
+var csvBuiltins = map[string]*object.Builtin{
+	"readCSV": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return newMisfield("wrong number of arguments. got=%d, want=1 or 2", len(args))
+			}
+			path, misfield := filePathArgument(ctx, "readCSV", len(args), args)
+			if misfield != nil {
+				return misfield
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fileMisfield("readCSV", err)
+			}
+			return parseCSV("readCSV", string(data), args[1:])
+		},
+	},
+	"parseCSV": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return newMisfield("wrong number of arguments. got=%d, want=1 or 2", len(args))
+			}
+			text, ok := args[0].(*object.String)
+			if !ok {
+				return newMisfield("argument to `parseCSV` must be STRING, got %s", args[0].Type())
+			}
+			return parseCSV("parseCSV", text.Value, args[1:])
+		},
+	},
+	"writeCSV": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			path, misfield := filePathArgument(ctx, "writeCSV", 2, args)
+			if misfield != nil {
+				return misfield
+			}
+			text, misfield := formatCSV("writeCSV", args[1])
+			if misfield != nil {
+				return misfield
+			}
+			if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+				return fileMisfield("writeCSV", err)
+			}
+			return DEAD_BALL
+		},
+	},
+	"formatCSV": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newMisfield("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			text, misfield := formatCSV("formatCSV", args[0])
+			if misfield != nil {
+				return misfield
+			}
+			return &object.String{Value: text}
+		},
+	},
+}
+
+func parseCSV(name, text string, options []object.Object) object.Object {
+	header, infer := false, true
+	if len(options) == 1 {
+		hash, ok := options[0].(*object.Hash)
+		if !ok {
+			return newMisfield("options for `%s` must be HASH, got %s", name, options[0].Type())
+		}
+		for _, key := range hash.Keys {
+			value, ok := hash.Pairs[key].(*object.Boolean)
+			if !ok {
+				return newMisfield("%s: option %s must be BOOLEAN, got %s", name, key, hash.Pairs[key].Type())
+			}
+			switch key {
+			case "header":
+				header = value.Value
+			case "infer":
+				infer = value.Value
+			default:
+				return newMisfield("%s: unknown option %s", name, key)
+			}
+		}
+	}
+
+	records, err := csv.NewReader(strings.NewReader(text)).ReadAll()
+	if err != nil {
+		return newMisfield("%s: %s", name, err)
+	}
+
+	var columns []string
+	if header && len(records) > 0 {
+		columns, records = records[0], records[1:]
+		seen := map[string]bool{}
+		for _, column := range columns {
+			if seen[column] {
+				return newMisfield("%s: column %s appears twice in the header", name, column)
+			}
+			seen[column] = true
+		}
+	}
+
+	integers := integerColumns(records, infer)
+	rows := make([]object.Object, len(records))
+	for i, record := range records {
+		cells := make([]object.Object, len(record))
+		for j, cell := range record {
+			if integers[j] {
+				value, _ := strconv.ParseInt(cell, 10, 64)
+				cells[j] = &object.Integer{Value: value}
+			} else {
+				cells[j] = &object.String{Value: cell}
+			}
+		}
+
+		if columns == nil {
+			rows[i] = &object.Array{Elements: cells}
+			continue
+		}
+		row := object.NewHash()
+		for j, column := range columns {
+			row.Set(column, cells[j])
+		}
+		rows[i] = row
+	}
+	return &object.Array{Elements: rows}
+}
+
+// integerColumns marks the columns where every cell is an integer. The csv
+// reader has already checked every record has the same number of fields.
+func integerColumns(records [][]string, infer bool) []bool {
+	if len(records) == 0 {
+		return nil
+	}
+	integers := make([]bool, len(records[0]))
+	if !infer {
+		return integers
+	}
+	for j := range integers {
+		integers[j] = true
+		for _, record := range records {
+			if _, err := strconv.ParseInt(record[j], 10, 64); err != nil {
+				integers[j] = false
+				break
+			}
+		}
+	}
+	return integers
+}
+
+// formatCSV writes rows, an array of arrays or of hashes. Rows of hashes
+// get a header row from the first hash's keys.
+func formatCSV(name string, arg object.Object) (string, *object.Misfield) {
+	rows, ok := arg.(*object.Array)
+	if !ok {
+		return "", newMisfield("rows for `%s` must be ARRAY, got %s", name, arg.Type())
+	}
+
+	var records [][]string
+	var columns []string
+	for _, row := range rows.Elements {
+		var cells []object.Object
+		switch row := row.(type) {
+		case *object.Array:
+			if columns != nil {
+				return "", newMisfield("%s: rows must all be arrays or all be hashes", name)
+			}
+			cells = row.Elements
+		case *object.Hash:
+			if columns == nil {
+				if len(records) > 0 {
+					return "", newMisfield("%s: rows must all be arrays or all be hashes", name)
+				}
+				columns = row.Keys
+				records = append(records, columns)
+			}
+			for _, key := range row.Keys {
+				if !containsString(columns, key) {
+					return "", newMisfield("%s: column %s is not in the header", name, key)
+				}
+			}
+			for _, column := range columns {
+				cell, ok := row.Get(column)
+				if !ok {
+					cell = DEAD_BALL
+				}
+				cells = append(cells, cell)
+			}
+		default:
+			return "", newMisfield("%s: each row must be ARRAY or HASH, got %s", name, row.Type())
+		}
+
+		record := make([]string, len(cells))
+		for i, cell := range cells {
+			switch cell := cell.(type) {
+			case *object.String:
+				record[i] = cell.Value
+			case *object.Integer, *object.Float, *object.Boolean:
+				record[i] = cell.Inspect()
+			case *object.DeadBallNull:
+				record[i] = ""
+			default:
+				return "", newMisfield("%s: cannot write %s as a CSV cell", name, cell.Type())
+			}
+		}
+		records = append(records, record)
+	}
+
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+	if err := w.WriteAll(records); err != nil {
+		return "", newMisfield("%s: %s", name, err)
+	}
+	return out.String(), nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package evaluator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCSVBuiltins(t *testing.T) {
+	root := writeModules(t, map[string]string{
+		"scores.csv": "batter,runs,balls\nKohli,82,53\nRohit,45,30\n",
+		"quoted.csv": "batter,note\n\"Dhoni, MS\",\"finished \"\"off\"\" in style\nwith a six\"\n",
+		"mixed.csv":  "match,score\n1,280\n2,DNB\n",
+		"ragged.csv": "a,b\n1\n",
+		"bad.csv":    "a,\"b\n",
+	})
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`readCSV("scores.csv")[1][1]`, "82"},
+		{`readCSV("scores.csv")[0][1]`, "runs"},
+		{`readCSV("scores.csv", {"header": notout})[0]["runs"]`, 82},
+		{`readCSV("scores.csv", {"header": notout})[1]["batter"]`, "Rohit"},
+		{`len(readCSV("scores.csv", {"header": notout}))`, 2},
+		{`readCSV("scores.csv", {"header": notout, "infer": out})[0]["runs"]`, "82"},
+		{`readCSV("quoted.csv", {"header": notout})[0]["batter"]`, "Dhoni, MS"},
+		{`readCSV("quoted.csv", {"header": notout})[0]["note"]`, "finished \"off\" in style\nwith a six"},
+		{`readCSV("mixed.csv", {"header": notout})[0]["match"]`, 1},
+		{`readCSV("mixed.csv", {"header": notout})[0]["score"]`, "280"},
+		{`readCSV("ragged.csv")`, "readCSV: record on line 2: wrong number of fields"},
+		{`readCSV("bad.csv")`, "readCSV: parse error on line 1, column 6: extraneous or missing \" in quoted-field"},
+		{`readCSV("scores.csv", {"headers": notout})`, "readCSV: unknown option headers"},
+		{`readCSV("scores.csv", {"header": 1})`, "readCSV: option header must be BOOLEAN, got INTEGER"},
+		{`readCSV("nope.csv")`, "readCSV: nope.csv: no such file or directory"},
+		{`readCSV("../scores.csv")`, "readCSV: ../scores.csv is outside the allowed directories"},
+		{`writeCSV("out.csv", [["batter", "runs"], ["Kohli", 82]]); readFile("out.csv")`, "batter,runs\nKohli,82\n"},
+		{`writeCSV("out.csv", [{"batter": "Dhoni, MS", "runs": 38}]); readCSV("out.csv", {"header": notout})[0]["batter"]`, "Dhoni, MS"},
+		{`writeCSV("out.csv", 1)`, "rows for `writeCSV` must be ARRAY, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testBuiltinResult(t, tt.input, testEvalWithFiles(tt.input, root, root), tt.expected)
+	}
+}
+
+func TestCSVNeedsFileAccess(t *testing.T) {
+	root := writeModules(t, map[string]string{"scores.csv": "a\n1\n"})
+
+	testBuiltinResult(t, "readCSV", testEvalWithFiles(`readCSV("scores.csv")`, root), "readCSV: file access is disabled")
+	testBuiltinResult(t, "writeCSV", testEvalWithFiles(`writeCSV("out.csv", [])`, root), "writeCSV: file access is disabled")
+	if _, err := os.Stat(filepath.Join(root, "out.csv")); err == nil {
+		t.Errorf("writeCSV wrote a file with file access disabled")
+	}
+}
+
+func TestParseAndFormatCSV(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`parseCSV("a,b
+1,2")[1][0]`, "1"},
+		{`parseCSV("1,2
+3,4")[1][0]`, 3},
+		{`parseCSV("name,team
+Kohli,India", {"header": notout})[0]["team"]`, "India"},
+		{`len(parseCSV(""))`, 0},
+		{`len(parseCSV("a,b", {"header": notout}))`, 0},
+		{`parseCSV("a,a
+1,2", {"header": notout})`, "parseCSV: column a appears twice in the header"},
+		{`formatCSV([["Dhoni, MS", 38], ["line
+break", float(5) / 2], [notout, appeal (out) { 1 }]])`, "\"Dhoni, MS\",38\n\"line\nbreak\",2.5\ntrue,\n"},
+		{`formatCSV([{"batter": "Kohli", "runs": 82}, {"runs": 45}])`, "batter,runs\nKohli,82\n,45\n"},
+		{`formatCSV([{"batter": "Kohli"}, {"runs": 45}])`, "formatCSV: column runs is not in the header"},
+		{`formatCSV([["a"], {"a": 1}])`, "formatCSV: rows must all be arrays or all be hashes"},
+		{`formatCSV([[[1]]])`, "formatCSV: cannot write ARRAY as a CSV cell"},
+		{`formatCSV([1])`, "formatCSV: each row must be ARRAY or HASH, got INTEGER"},
+		{`parseCSV(formatCSV([["x,y", "z"]]))[0][0]`, "x,y"},
+		{`parseCSV(1)`, "argument to `parseCSV` must be STRING, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testBuiltinResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
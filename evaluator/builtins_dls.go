@@ -0,0 +1,194 @@
+package evaluator
+
+import (
+	"CricLang/dls"
+	"CricLang/object"
+)
+
+var dlsBuiltins = map[string]*object.Builtin{
+	"dls": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			match, misfield := hashArgument("dls", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			return evalDLS(match)
+		},
+	},
+	"dlsResources": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) != 3 {
+				return newMisfield("wrong number of arguments. got=%d, want=3", len(args))
+			}
+			format, ok := args[0].(*object.Integer)
+			if !ok {
+				return newMisfield("format for `dlsResources` must be INTEGER, got %s", args[0].Type())
+			}
+			balls, misfield := oversArgument("dlsResources", args[1])
+			if misfield != nil {
+				return misfield
+			}
+			wickets, ok := args[2].(*object.Integer)
+			if !ok {
+				return newMisfield("wickets for `dlsResources` must be INTEGER, got %s", args[2].Type())
+			}
+
+			pct, err := dls.Resources(format.Value, balls, int(wickets.Value))
+			if err != nil {
+				return newMisfield("dlsResources: %s", err)
+			}
+			return &object.Float{Value: pct}
+		},
+	},
+}
+
+// evalDLS reads a match hash:
+//
+//	{"overs": 50, "score": 250, "stoppages": [...], "g": 245, "at": {...}}
+//
+// Each stoppage is {"innings", "overs", "wickets", "score", "lost"}, with
+// "overs" the overs bowled when play stopped and "lost" the overs taken
+// off. "at" is {"overs", "wickets"} in the chase and asks for a par score.
+func evalDLS(match *object.Hash) object.Object {
+	m := dls.Match{}
+	var misfield *object.Misfield
+
+	if m.Overs, misfield = dlsInteger(match, "overs", "match", true); misfield != nil {
+		return misfield
+	}
+	if m.Team1Score, misfield = dlsInteger(match, "score", "match", true); misfield != nil {
+		return misfield
+	}
+	if g, ok := match.Get("g"); ok {
+		if !isNumber(g) {
+			return newMisfield("dls: match g must be INTEGER or FLOAT, got %s", g.Type())
+		}
+		m.G = toFloat(g)
+	}
+
+	if stoppages, ok := match.Get("stoppages"); ok {
+		array, ok := stoppages.(*object.Array)
+		if !ok {
+			return newMisfield("dls: match stoppages must be ARRAY, got %s", stoppages.Type())
+		}
+		for _, el := range array.Elements {
+			stoppage, ok := el.(*object.Hash)
+			if !ok {
+				return newMisfield("dls: each stoppage must be HASH, got %s", el.Type())
+			}
+			s, misfield := dlsStoppage(stoppage)
+			if misfield != nil {
+				return misfield
+			}
+			m.Stoppages = append(m.Stoppages, s)
+		}
+	}
+
+	result, err := dls.Calculate(m)
+	if err != nil {
+		return newMisfield("dls: %s", err)
+	}
+
+	out := object.NewHash()
+	out.Set("overs", &object.Integer{Value: result.Overs})
+	out.Set("team1Score", &object.Integer{Value: result.Team1Score})
+	out.Set("team1Overs", &object.String{Value: object.FormatOvers(result.Team1Balls)})
+	out.Set("team1Resources", &object.Float{Value: result.Team1Resources})
+	out.Set("team2Overs", &object.String{Value: object.FormatOvers(result.Team2Balls)})
+	out.Set("team2Resources", &object.Float{Value: result.Team2Resources})
+	out.Set("target", &object.Integer{Value: result.Target})
+
+	if at, ok := match.Get("at"); ok {
+		point, ok := at.(*object.Hash)
+		if !ok {
+			return newMisfield("dls: match at must be HASH, got %s", at.Type())
+		}
+		balls, misfield := dlsOvers(point, "overs", "at")
+		if misfield != nil {
+			return misfield
+		}
+		wickets, misfield := dlsInteger(point, "wickets", "at", false)
+		if misfield != nil {
+			return misfield
+		}
+		par, err := result.Par(balls, int(wickets))
+		if err != nil {
+			return newMisfield("dls: %s", err)
+		}
+		out.Set("par", &object.Integer{Value: par})
+	}
+
+	stoppages := []object.Object{}
+	for _, s := range result.Stoppages {
+		stoppage := object.NewHash()
+		stoppage.Set("innings", &object.Integer{Value: int64(s.Innings)})
+		stoppage.Set("overs", &object.String{Value: object.FormatOvers(s.Balls)})
+		stoppage.Set("score", &object.Integer{Value: s.Score})
+		stoppage.Set("wickets", &object.Integer{Value: int64(s.Wickets)})
+		stoppage.Set("lost", &object.Integer{Value: s.OversLost})
+		stoppage.Set("oversLeftBefore", &object.String{Value: object.FormatOvers(s.BallsBefore)})
+		stoppage.Set("oversLeftAfter", &object.String{Value: object.FormatOvers(s.BallsAfter)})
+		stoppage.Set("resourcesBefore", &object.Float{Value: s.ResourcesBefore})
+		stoppage.Set("resourcesAfter", &object.Float{Value: s.ResourcesAfter})
+		stoppage.Set("resourcesLost", &object.Float{Value: s.ResourcesLost})
+		stoppages = append(stoppages, stoppage)
+	}
+	out.Set("stoppages", &object.Array{Elements: stoppages})
+
+	explanation := []object.Object{}
+	for _, line := range result.Explain() {
+		explanation = append(explanation, &object.String{Value: line})
+	}
+	out.Set("explanation", &object.Array{Elements: explanation})
+
+	return out
+}
+
+func dlsStoppage(stoppage *object.Hash) (dls.Stoppage, *object.Misfield) {
+	s := dls.Stoppage{}
+	innings, misfield := dlsInteger(stoppage, "innings", "stoppage", true)
+	if misfield != nil {
+		return s, misfield
+	}
+	s.Innings = int(innings)
+	if s.Balls, misfield = dlsOvers(stoppage, "overs", "stoppage"); misfield != nil {
+		return s, misfield
+	}
+	wickets, misfield := dlsInteger(stoppage, "wickets", "stoppage", false)
+	if misfield != nil {
+		return s, misfield
+	}
+	s.Wickets = int(wickets)
+	if s.Score, misfield = dlsInteger(stoppage, "score", "stoppage", false); misfield != nil {
+		return s, misfield
+	}
+	if s.OversLost, misfield = dlsInteger(stoppage, "lost", "stoppage", true); misfield != nil {
+		return s, misfield
+	}
+	return s, nil
+}
+
+// dlsInteger reads an integer field of a dls hash; a missing optional field
+// is 0.
+func dlsInteger(hash *object.Hash, key, what string, required bool) (int64, *object.Misfield) {
+	value, ok := hash.Get(key)
+	if !ok {
+		if required {
+			return 0, newMisfield("dls: %s is missing %s", what, key)
+		}
+		return 0, nil
+	}
+	integer, ok := value.(*object.Integer)
+	if !ok {
+		return 0, newMisfield("dls: %s %s must be INTEGER, got %s", what, key, value.Type())
+	}
+	return integer.Value, nil
+}
+
+func dlsOvers(hash *object.Hash, key, what string) (int64, *object.Misfield) {
+	value, ok := hash.Get(key)
+	if !ok {
+		return 0, newMisfield("dls: %s is missing %s", what, key)
+	}
+	return oversArgument("dls", value)
+}
@@ -0,0 +1,49 @@
+package evaluator
+
+import "testing"
+
+func TestDLSBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`dls({"overs": 50, "score": 250})["target"]`, 251},
+		{`dls({"overs": 50, "score": 250, "stoppages": [{"innings": 1, "overs": "30.0", "wickets": 3, "score": 150, "lost": 10}]})["target"]`, 272},
+		{`dls({"overs": 50, "score": 250, "stoppages": [{"innings": 1, "overs": 180, "wickets": 3, "lost": 10}]})["team2Overs"]`, "40.0"},
+		{`dls({"overs": 50, "score": 250, "stoppages": [{"innings": 1, "overs": "30.0", "wickets": 3, "lost": 10}]})["stoppages"][0]["oversLeftAfter"]`, "10.0"},
+		{`dls({"overs": 50, "score": 250, "stoppages": [{"innings": 2, "overs": "20.0", "wickets": 2, "lost": 20}], "at": {"overs": "25.0", "wickets": 3}})["par"]`, 117},
+		{`dls({"overs": 50, "score": 250, "at": {"overs": "50.0"}})["par"]`, 250},
+		{`len(dls({"overs": 20, "score": 180})["explanation"])`, 4},
+		{`dls({"overs": 50})`, "dls: match is missing score"},
+		{`dls({"overs": 50, "score": "250"})`, "dls: match score must be INTEGER, got STRING"},
+		{`dls({"overs": 40, "score": 250})`, "dls: DLS supports 50 and 20 over matches, got 40"},
+		{`dls({"overs": 50, "score": 250, "stoppages": [1]})`, "dls: each stoppage must be HASH, got INTEGER"},
+		{`dls({"overs": 50, "score": 250, "stoppages": [{"innings": 1, "overs": "30.0"}]})`, "dls: stoppage is missing lost"},
+		{`dls({"overs": 50, "score": 250, "stoppages": [{"innings": 1, "overs": "30.7", "lost": 1}]})`, `dls: invalid overs "30.7": the part after the point counts balls, 0 to 5`},
+		{`dls({"overs": 50, "score": 250, "at": {"overs": "51.0"}})`, "dls: team 2 only has 50.0 overs"},
+		{`dls(50)`, "argument to `dls` must be HASH, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		testBuiltinResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestDLSResourcesBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`dlsResources(50, "20.0", 0)`, 56.6},
+		{`dlsResources(50, "50.0", 5)`, 49.0},
+		{`dlsResources(50, 300, 0)`, 100.0},
+		{`dlsResources(20, "20.0", 0)`, 100.0},
+		{`dlsResources(50, "20.0", 10)`, 0.0},
+		{`dlsResources(50, "20.0", 11)`, "dlsResources: wickets must be 0 to 10, got 11"},
+		{`dlsResources("50", 1, 0)`, "format for `dlsResources` must be INTEGER, got STRING"},
+	}
+
+	for _, tt := range tests {
+		testMathResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
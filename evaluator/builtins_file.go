@@ -0,0 +1,202 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The file builtins only see the directories listed in the runtime's
+// FileRoots. Relative paths are resolved like imports, against the
+// directory of the running file.
+var fileBuiltins = map[string]*object.Builtin{
+	"readFile": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			path, misfield := filePathArgument(ctx, "readFile", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fileMisfield("readFile", err)
+			}
+			return &object.String{Value: string(data)}
+		},
+	},
+	"writeFile": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			return writeFileBuiltin(ctx, "writeFile", os.O_TRUNC, args)
+		},
+	},
+	"appendFile": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			return writeFileBuiltin(ctx, "appendFile", os.O_APPEND, args)
+		},
+	},
+	"readLines": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			path, misfield := filePathArgument(ctx, "readLines", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fileMisfield("readLines", err)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+			if len(data) == 0 {
+				lines = nil
+			}
+			elements := make([]object.Object, len(lines))
+			for i, line := range lines {
+				elements[i] = &object.String{Value: strings.TrimSuffix(line, "\r")}
+			}
+			return &object.Array{Elements: elements}
+		},
+	},
+	"listDir": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			path, misfield := filePathArgument(ctx, "listDir", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			entries, err := os.ReadDir(path)
+			if err != nil {
+				return fileMisfield("listDir", err)
+			}
+
+			names := make([]string, len(entries))
+			for i, entry := range entries {
+				names[i] = entry.Name()
+			}
+			sort.Strings(names)
+
+			elements := make([]object.Object, len(names))
+			for i, name := range names {
+				elements[i] = &object.String{Value: name}
+			}
+			return &object.Array{Elements: elements}
+		},
+	},
+	"exists": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			path, misfield := filePathArgument(ctx, "exists", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			_, err := os.Stat(path)
+			return nativeBoolToBooleanObject(err == nil)
+		},
+	},
+}
+
+func writeFileBuiltin(ctx *object.CallContext, name string, mode int, args []object.Object) object.Object {
+	path, misfield := filePathArgument(ctx, name, 2, args)
+	if misfield != nil {
+		return misfield
+	}
+	content, ok := args[1].(*object.String)
+	if !ok {
+		return newMisfield("content for `%s` must be STRING, got %s", name, args[1].Type())
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|mode, 0o644)
+	if err != nil {
+		return fileMisfield(name, err)
+	}
+	_, err = file.WriteString(content.Value)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fileMisfield(name, err)
+	}
+	return DEAD_BALL
+}
+
+// filePathArgument checks a file builtin got want arguments, the first a
+// path, and resolves that path inside the allowed roots.
+func filePathArgument(ctx *object.CallContext, name string, want int, args []object.Object) (string, *object.Misfield) {
+	if len(args) != want {
+		return "", newMisfield("wrong number of arguments. got=%d, want=%d", len(args), want)
+	}
+	path, ok := args[0].(*object.String)
+	if !ok {
+		return "", newMisfield("path for `%s` must be STRING, got %s", name, args[0].Type())
+	}
+
+	runtime := ctx.Env.Runtime()
+	if len(runtime.FileRoots) == 0 {
+		return "", newMisfield("%s: file access is disabled", name)
+	}
+
+	resolved, ok := resolveFilePath(path.Value, ctx.Env.Dir(), runtime.FileRoots)
+	if !ok {
+		return "", newMisfield("%s: %s is outside the allowed directories", name, path.Value)
+	}
+	return resolved, nil
+}
+
+// resolveFilePath makes path absolute and reports whether it lies inside
+// one of roots. Symlinks are followed first, so a link can't lead out of a
+// root. A path that doesn't exist yet is judged by its closest existing
+// ancestor.
+func resolveFilePath(path, dir string, roots []string) (string, bool) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+
+	resolved, ok := evalSymlinks(abs)
+	if !ok {
+		return "", false
+	}
+
+	for _, root := range roots {
+		rootAbs, err := filepath.Abs(root)
+		if err != nil {
+			continue
+		}
+		if realRoot, err := filepath.EvalSymlinks(rootAbs); err == nil {
+			rootAbs = realRoot
+		}
+		rel, err := filepath.Rel(rootAbs, resolved)
+		if err != nil {
+			continue
+		}
+		if rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return resolved, true
+		}
+	}
+	return "", false
+}
+
+// evalSymlinks resolves the symlinks in the existing part of an absolute
+// path and keeps the rest as it is. A dangling symlink fails: it can't be
+// judged by where it points, and writing through it would create its target.
+func evalSymlinks(abs string) (string, bool) {
+	missing := ""
+	for current := abs; ; current = filepath.Dir(current) {
+		if resolved, err := filepath.EvalSymlinks(current); err == nil {
+			return filepath.Join(resolved, missing), true
+		}
+		if _, err := os.Lstat(current); err == nil || current == filepath.Dir(current) {
+			return "", false
+		}
+		missing = filepath.Join(filepath.Base(current), missing)
+	}
+}
+
+// fileMisfield reports an OS error without the absolute path it names.
+func fileMisfield(name string, err error) *object.Misfield {
+	if pathErr, ok := err.(*os.PathError); ok {
+		return newMisfield("%s: %s: %s", name, filepath.Base(pathErr.Path), pathErr.Err)
+	}
+	return newMisfield("%s: %s", name, err)
+}
@@ -0,0 +1,100 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testEvalWithFiles(input string, dir string, roots ...string) object.Object {
+	runtime := object.NewRuntime()
+	runtime.FileRoots = roots
+	return testEvalIn(input, runtime, dir)
+}
+
+func TestFileBuiltins(t *testing.T) {
+	root := writeModules(t, map[string]string{
+		"balls.txt":      "1\n4\r\nW\n",
+		"empty.txt":      "",
+		"match/inn1.txt": "",
+		"match/inn2.txt": "",
+	})
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`readFile("balls.txt")`, "1\n4\r\nW\n"},
+		{`join(readLines("balls.txt"), ",")`, "1,4,W"},
+		{`len(readLines("empty.txt"))`, 0},
+		{`join(listDir("match"), ",")`, "inn1.txt,inn2.txt"},
+		{`exists("balls.txt")`, true},
+		{`exists("nope.txt")`, false},
+		{`exists("nope/deeper.txt")`, false},
+		{`writeFile("out.txt", "six"); readFile("out.txt")`, "six"},
+		{`writeFile("out.txt", "four"); writeFile("out.txt", "six"); readFile("out.txt")`, "six"},
+		{`writeFile("log.txt", "a"); appendFile("log.txt", "b"); appendFile("log.txt", "c"); readFile("log.txt")`, "abc"},
+		{`appendFile("new.txt", "x"); readFile("new.txt")`, "x"},
+		{`readFile("missing.txt")`, "readFile: missing.txt: no such file or directory"},
+		{`writeFile("out.txt", 5)`, "content for `writeFile` must be STRING, got INTEGER"},
+		{`readFile(5)`, "path for `readFile` must be STRING, got INTEGER"},
+		{`readFile()`, "wrong number of arguments. got=0, want=1"},
+		{`readFile("match/../balls.txt")`, "1\n4\r\nW\n"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEvalWithFiles(tt.input, root, root)
+		if expected, ok := tt.expected.(bool); ok {
+			testBooleanObject(t, evaluated, expected)
+			continue
+		}
+		testBuiltinResult(t, tt.input, evaluated, tt.expected)
+	}
+}
+
+func TestFileBuiltinsAreSandboxed(t *testing.T) {
+	outside := writeModules(t, map[string]string{"secret.txt": "s3cret"})
+	root := writeModules(t, map[string]string{"data/balls.txt": "1"})
+	if err := os.Symlink(outside, filepath.Join(root, "data", "escape")); err != nil {
+		t.Skipf("cannot create symlink: %s", err)
+	}
+	dangling := filepath.Join(outside, "planted.txt")
+	if err := os.Symlink(dangling, filepath.Join(root, "data", "dangling.txt")); err != nil {
+		t.Fatal(err)
+	}
+	data := filepath.Join(root, "data")
+	secret := filepath.Join(outside, "secret.txt")
+
+	tests := []struct {
+		input    string
+		roots    []string
+		expected string
+	}{
+		{`readFile("balls.txt")`, nil, "readFile: file access is disabled"},
+		{`exists("balls.txt")`, nil, "exists: file access is disabled"},
+		{`readFile("../data/balls.txt")`, []string{data}, "1"},
+		{`readFile("../secret.txt")`, []string{data}, "readFile: ../secret.txt is outside the allowed directories"},
+		{`readFile("` + secret + `")`, []string{data}, "readFile: " + secret + " is outside the allowed directories"},
+		{`readFile("escape/secret.txt")`, []string{data}, "readFile: escape/secret.txt is outside the allowed directories"},
+		{`writeFile("escape/new.txt", "x")`, []string{data}, "writeFile: escape/new.txt is outside the allowed directories"},
+		{`writeFile("dangling.txt", "x")`, []string{data}, "writeFile: dangling.txt is outside the allowed directories"},
+		{`appendFile("dangling.txt", "x")`, []string{data}, "appendFile: dangling.txt is outside the allowed directories"},
+		{`writeCSV("dangling.txt", [["x"]])`, []string{data}, "writeCSV: dangling.txt is outside the allowed directories"},
+		{`readFile("dangling.txt")`, []string{data}, "readFile: dangling.txt is outside the allowed directories"},
+		{`listDir("..")`, []string{data}, "listDir: .. is outside the allowed directories"},
+		{`readFile("escape/secret.txt")`, []string{data, outside}, "s3cret"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEvalWithFiles(tt.input, data, tt.roots...)
+		testBuiltinResult(t, tt.input, evaluated, tt.expected)
+	}
+
+	if _, err := os.Stat(filepath.Join(outside, "new.txt")); err == nil {
+		t.Errorf("writeFile escaped the sandbox through a symlink")
+	}
+	if _, err := os.Lstat(dangling); err == nil {
+		t.Errorf("a write escaped the sandbox through a dangling symlink")
+	}
+}
@@ -0,0 +1,58 @@
+package evaluator
+
+import "CricLang/object"
+
+var hashBuiltins = map[string]*object.Builtin{
+	"keys": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			hash, misfield := hashArgument("keys", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			elements := make([]object.Object, len(hash.Keys))
+			for i, key := range hash.Keys {
+				elements[i] = &object.String{Value: key}
+			}
+			return &object.Array{Elements: elements}
+		},
+	},
+	"values": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			hash, misfield := hashArgument("values", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			elements := make([]object.Object, len(hash.Keys))
+			for i, key := range hash.Keys {
+				elements[i] = hash.Pairs[key]
+			}
+			return &object.Array{Elements: elements}
+		},
+	},
+	"has": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			hash, misfield := hashArgument("has", 2, args)
+			if misfield != nil {
+				return misfield
+			}
+			key, ok := args[1].(*object.String)
+			if !ok {
+				return newMisfield("unusable as hash key: %s", args[1].Type())
+			}
+			_, found := hash.Get(key.Value)
+			return nativeBoolToBooleanObject(found)
+		},
+	},
+}
+
+// hashArgument checks a builtin got want arguments, the first a hash.
+func hashArgument(name string, want int, args []object.Object) (*object.Hash, *object.Misfield) {
+	if len(args) != want {
+		return nil, newMisfield("wrong number of arguments. got=%d, want=%d", len(args), want)
+	}
+	hash, ok := args[0].(*object.Hash)
+	if !ok {
+		return nil, newMisfield("argument to `%s` must be HASH, got %s", name, args[0].Type())
+	}
+	return hash, nil
+}
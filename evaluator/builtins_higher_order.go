@@ -0,0 +1,196 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"sort"
+)
+
+// These builtins take a field and call it through ctx.Apply. A Misfield
+// from the field stops the builtin and is returned as its result.
+var higherOrderBuiltins = map[string]*object.Builtin{
+	"map": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			arr, fn, misfield := arrayAndField("map", args)
+			if misfield != nil {
+				return misfield
+			}
+			elements := make([]object.Object, len(arr.Elements))
+			for i, e := range arr.Elements {
+				result := ctx.Apply(fn, e)
+				if isMisfield(result) {
+					return result
+				}
+				elements[i] = result
+			}
+			return &object.Array{Elements: elements}
+		},
+	},
+	"filter": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			arr, fn, misfield := arrayAndField("filter", args)
+			if misfield != nil {
+				return misfield
+			}
+			elements := []object.Object{}
+			for _, e := range arr.Elements {
+				result := ctx.Apply(fn, e)
+				if isMisfield(result) {
+					return result
+				}
+				if isTruthy(result) {
+					elements = append(elements, e)
+				}
+			}
+			return &object.Array{Elements: elements}
+		},
+	},
+	"reduce": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) != 2 && len(args) != 3 {
+				return newMisfield("wrong number of arguments. got=%d, want=2 or 3", len(args))
+			}
+			arr, fn, misfield := arrayAndField("reduce", args[:2])
+			if misfield != nil {
+				return misfield
+			}
+
+			elements := arr.Elements
+			var acc object.Object
+			if len(args) == 3 {
+				acc = args[2]
+			} else if len(elements) == 0 {
+				return newMisfield("reduce of empty ARRAY with no initial value")
+			} else {
+				acc, elements = elements[0], elements[1:]
+			}
+
+			for _, e := range elements {
+				acc = ctx.Apply(fn, acc, e)
+				if isMisfield(acc) {
+					return acc
+				}
+			}
+			return acc
+		},
+	},
+	"sort": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) == 1 {
+				return sortNatural(args[0])
+			}
+			arr, fn, misfield := arrayAndField("sort", args)
+			if misfield != nil {
+				return misfield
+			}
+
+			var failed object.Object
+			elements := newArray(arr.Elements).Elements
+			sort.SliceStable(elements, func(i, j int) bool {
+				if failed != nil {
+					return false
+				}
+				result := ctx.Apply(fn, elements[i], elements[j])
+				if isMisfield(result) {
+					failed = result
+					return false
+				}
+				return isTruthy(result)
+			})
+			if failed != nil {
+				return failed
+			}
+			return &object.Array{Elements: elements}
+		},
+	},
+	"any": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			index := findIndex(ctx, "any", args)
+			if isMisfield(index) {
+				return index
+			}
+			return nativeBoolToBooleanObject(index.(*object.Integer).Value >= 0)
+		},
+	},
+	"all": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			arr, fn, misfield := arrayAndField("all", args)
+			if misfield != nil {
+				return misfield
+			}
+			for _, e := range arr.Elements {
+				result := ctx.Apply(fn, e)
+				if isMisfield(result) {
+					return result
+				}
+				if !isTruthy(result) {
+					return OUT
+				}
+			}
+			return NOT_OUT
+		},
+	},
+	"findIndex": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			return findIndex(ctx, "findIndex", args)
+		},
+	},
+}
+
+// arrayAndField checks the (array, field) arguments most of these take.
+func arrayAndField(name string, args []object.Object) (*object.Array, object.Object, *object.Misfield) {
+	arr, misfield := arrayArgument(name, 2, args)
+	if misfield != nil {
+		return nil, nil, misfield
+	}
+	switch args[1].(type) {
+	case *object.Field, *object.Builtin:
+		return arr, args[1], nil
+	default:
+		return nil, nil, newMisfield("second argument to `%s` must be FIELD, got %s", name, args[1].Type())
+	}
+}
+
+// findIndex returns the index of the first element the field accepts, or -1.
+func findIndex(ctx *object.CallContext, name string, args []object.Object) object.Object {
+	arr, fn, misfield := arrayAndField(name, args)
+	if misfield != nil {
+		return misfield
+	}
+	for i, e := range arr.Elements {
+		result := ctx.Apply(fn, e)
+		if isMisfield(result) {
+			return result
+		}
+		if isTruthy(result) {
+			return &object.Integer{Value: int64(i)}
+		}
+	}
+	return &object.Integer{Value: -1}
+}
+
+// sortNatural sorts an array of integers or of strings in ascending order.
+func sortNatural(arg object.Object) object.Object {
+	arr, ok := arg.(*object.Array)
+	if !ok {
+		return newMisfield("argument to `sort` must be ARRAY, got %s", arg.Type())
+	}
+	elements := newArray(arr.Elements).Elements
+	if len(elements) == 0 {
+		return &object.Array{Elements: elements}
+	}
+
+	kind := elements[0].Type()
+	for _, e := range elements {
+		if e.Type() != kind || (kind != object.INTEGER_OBJ && kind != object.STRING_OBJ) {
+			return newMisfield("sort without a comparator needs all INTEGER or all STRING, got %s", e.Type())
+		}
+	}
+
+	sort.SliceStable(elements, func(i, j int) bool {
+		if kind == object.INTEGER_OBJ {
+			return elements[i].(*object.Integer).Value < elements[j].(*object.Integer).Value
+		}
+		return elements[i].(*object.String).Value < elements[j].(*object.String).Value
+	})
+	return &object.Array{Elements: elements}
+}
@@ -0,0 +1,64 @@
+package evaluator
+
+import "testing"
+
+func TestHigherOrderBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`map([1, 2, 3], field(x) { x * 2 })`, []int{2, 4, 6}},
+		{`map([], field(x) { x * 2 })`, []int{}},
+		{`map([[1], [1, 2]], len)`, []int{1, 2}},
+		{`map([1, 2], 5)`, "second argument to `map` must be FIELD, got INTEGER"},
+		{`map([1, 2], field(x) { x + "a" })`, "player type mismatch: INTEGER + STRING"},
+		{`map([1, 2], field(x, y) { x })`, "wrong number of arguments to field. got=1, want=2"},
+		{`filter([1, 2, 3, 4], field(x) { x > 2 })`, []int{3, 4}},
+		{`filter([1, 2, 3], field(x) { out })`, []int{}},
+		{`reduce([1, 2, 3, 4], field(acc, x) { acc + x })`, 10},
+		{`reduce([1, 2, 3, 4], field(acc, x) { acc + x }, 10)`, 20},
+		{`reduce([], field(acc, x) { acc + x }, 7)`, 7},
+		{`reduce([], field(acc, x) { acc + x })`, "reduce of empty ARRAY with no initial value"},
+		{`sort([3, 1, 2])`, []int{1, 2, 3}},
+		{`sort([3, 1, 2], field(a, b) { a > b })`, []int{3, 2, 1}},
+		{`first(sort(["kohli", "dhoni", "rohit"]))`, "dhoni"},
+		{`sort([1, "a"])`, "sort without a comparator needs all INTEGER or all STRING, got STRING"},
+		{`sort([2, 1], field(a, b) { a + notout })`, "player type mismatch: INTEGER + BOOLEAN"},
+		{`any([1, 2, 3], field(x) { x == 2 })`, true},
+		{`any([], field(x) { notout })`, false},
+		{`all([1, 2, 3], field(x) { x > 0 })`, true},
+		{`all([1, 2, 3], field(x) { x > 1 })`, false},
+		{`findIndex([5, 6, 7], field(x) { x == 7 })`, 2},
+		{`findIndex([5, 6, 7], field(x) { x == 8 })`, -1},
+		{`findIndex([5], field(x) { y })`, "identifier not found: y"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if expected, ok := tt.expected.(bool); ok {
+			testBooleanObject(t, evaluated, expected)
+			continue
+		}
+		testBuiltinResult(t, tt.input, evaluated, tt.expected)
+	}
+}
+
+func TestSortByStrikeRate(t *testing.T) {
+	input := `
+	player batters = [[30, 20], [50, 25], [10, 20]];
+	player strikeRate = field(card) { card[0] * 100 / card[1] };
+	player order = sort(batters, field(a, b) { strikeRate(a) > strikeRate(b) });
+	map(order, strikeRate)
+	`
+
+	testBuiltinResult(t, input, testEval(input), []int{200, 150, 50})
+}
+
+func TestSortIsStable(t *testing.T) {
+	input := `
+	player pairs = [[1, 1], [0, 2], [1, 3], [0, 4]];
+	map(sort(pairs, field(a, b) { a[0] < b[0] }), field(p) { p[1] })
+	`
+
+	testBuiltinResult(t, input, testEval(input), []int{2, 4, 1, 3})
+}
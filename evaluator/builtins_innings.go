@@ -0,0 +1,141 @@
+package evaluator
+
+import "CricLang/object"
+
+var inningsBuiltins = map[string]*object.Builtin{
+	"innings": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) != 2 && len(args) != 3 {
+				return newMisfield("wrong number of arguments. got=%d, want=2 or 3", len(args))
+			}
+			team, ok := args[0].(*object.String)
+			if !ok {
+				return newMisfield("team for `innings` must be STRING, got %s", args[0].Type())
+			}
+			order, ok := args[1].(*object.Array)
+			if !ok {
+				return newMisfield("batting order for `innings` must be ARRAY, got %s", args[1].Type())
+			}
+			names := make([]string, len(order.Elements))
+			for i, el := range order.Elements {
+				name, ok := el.(*object.String)
+				if !ok {
+					return newMisfield("batters for `innings` must be STRING, got %s", el.Type())
+				}
+				names[i] = name.Value
+			}
+			var overs int64
+			if len(args) == 3 {
+				limit, ok := args[2].(*object.Integer)
+				if !ok || limit.Value < 1 {
+					return newMisfield("overs for `innings` must be a positive INTEGER, got %s", args[2].Inspect())
+				}
+				overs = limit.Value
+			}
+
+			inn, err := object.NewInnings(team.Value, names, overs)
+			if err != nil {
+				return newMisfield("innings: %s", err)
+			}
+			return inn
+		},
+	},
+	"ball":   deliveryBuiltin("ball", 2, (*object.Innings).Runs),
+	"wide":   deliveryBuiltin("wide", 1, (*object.Innings).Wide),
+	"noBall": deliveryBuiltin("noBall", 1, (*object.Innings).NoBall),
+	"bye":    deliveryBuiltin("bye", 2, (*object.Innings).Bye),
+	"legBye": deliveryBuiltin("legBye", 2, (*object.Innings).LegBye),
+	"wicket": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) < 2 || len(args) > 4 {
+				return newMisfield("wrong number of arguments. got=%d, want=2 to 4", len(args))
+			}
+			inn, misfield := inningsArgument("wicket", args)
+			if misfield != nil {
+				return misfield
+			}
+			kind, ok := args[1].(*object.String)
+			if !ok {
+				return newMisfield("dismissal for `wicket` must be STRING, got %s", args[1].Type())
+			}
+			runs, misfield := runsArgument("wicket", args, 2)
+			if misfield != nil {
+				return misfield
+			}
+			nonStriker := false
+			if len(args) == 4 {
+				end, ok := args[3].(*object.String)
+				if !ok || (end.Value != "striker" && end.Value != "nonStriker") {
+					return newMisfield("end for `wicket` must be \"striker\" or \"nonStriker\", got %s", args[3].Inspect())
+				}
+				nonStriker = end.Value == "nonStriker"
+			}
+
+			if err := inn.Wicket(kind.Value, runs, nonStriker); err != nil {
+				return newMisfield("wicket: %s", err)
+			}
+			return inn
+		},
+	},
+}
+
+// deliveryBuiltin builds the builtins that record runs off one delivery.
+// With minArgs of 1 the runs are optional and default to 0.
+func deliveryBuiltin(name string, minArgs int, record func(*object.Innings, int64) error) *object.Builtin {
+	return &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) < minArgs || len(args) > 2 {
+				if minArgs == 2 {
+					return newMisfield("wrong number of arguments. got=%d, want=2", len(args))
+				}
+				return newMisfield("wrong number of arguments. got=%d, want=1 or 2", len(args))
+			}
+			inn, misfield := inningsArgument(name, args)
+			if misfield != nil {
+				return misfield
+			}
+			runs, misfield := runsArgument(name, args, 1)
+			if misfield != nil {
+				return misfield
+			}
+
+			if err := record(inn, runs); err != nil {
+				return newMisfield("%s: %s", name, err)
+			}
+			return inn
+		},
+	}
+}
+
+func inningsArgument(name string, args []object.Object) (*object.Innings, *object.Misfield) {
+	inn, ok := args[0].(*object.Innings)
+	if !ok {
+		return nil, newMisfield("argument to `%s` must be INNINGS, got %s", name, args[0].Type())
+	}
+	return inn, nil
+}
+
+// runsArgument reads the runs at args[i], which may be left off for 0.
+func runsArgument(name string, args []object.Object, i int) (int64, *object.Misfield) {
+	if len(args) <= i {
+		return 0, nil
+	}
+	runs, ok := args[i].(*object.Integer)
+	if !ok {
+		return 0, newMisfield("runs for `%s` must be INTEGER, got %s", name, args[i].Type())
+	}
+	return runs.Value, nil
+}
+
+func evalInningsIndexExpression(innings, index object.Object) object.Object {
+	inn := innings.(*object.Innings)
+	name := index.(*object.String).Value
+
+	if name == "over" {
+		return nativeBoolToBooleanObject(inn.Over())
+	}
+	if value, ok := inn.Attribute(name); ok {
+		return value
+	}
+	return newMisfield("innings has no %s", name)
+}
@@ -0,0 +1,129 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"strings"
+	"testing"
+)
+
+const inningsSetup = `player inn = innings("India", ["Rohit", "Gill", "Kohli", "Iyer"], 20);`
+
+func TestInningsRecording(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`ball(inn, 4); ball(inn, 6); inn["runs"]`, 10},
+		{`ball(inn, 1); inn["striker"]`, "Gill"},
+		{`ball(inn, 2); inn["striker"]`, "Rohit"},
+		{`ball(inn, 0); ball(inn, 0); ball(inn, 0); ball(inn, 0); ball(inn, 0); ball(inn, 0); inn["striker"]`, "Gill"},
+		{`ball(inn, 0); ball(inn, 0); ball(inn, 0); ball(inn, 0); ball(inn, 0); ball(inn, 1); inn["striker"]`, "Rohit"},
+		{`wide(inn); wide(inn, 4); inn["runs"]`, 6},
+		{`wide(inn); inn["balls"]`, 0},
+		{`wide(inn, 1); inn["striker"]`, "Gill"},
+		{`noBall(inn, 6); inn["runs"]`, 7},
+		{`noBall(inn); inn["overs"]`, "0.0"},
+		{`bye(inn, 1); legBye(inn, 4); inn["overs"]`, "0.2"},
+		{`bye(inn, 1); legBye(inn, 4); inn["extras"]["total"]`, 5},
+		{`bye(inn, 1); inn["striker"]`, "Gill"},
+		{`wicket(inn, "bowled"); inn["striker"]`, "Kohli"},
+		{`wicket(inn, "bowled"); inn["nonStriker"]`, "Gill"},
+		{`wicket(inn, "run out", 1, "nonStriker"); inn["striker"]`, "Gill"},
+		{`wicket(inn, "run out", 1, "nonStriker"); inn["nonStriker"]`, "Kohli"},
+		{`wicket(inn, "caught"); wicket(inn, "lbw"); wicket(inn, "stumped"); inn["over"]`, true},
+		{`wicket(inn, "caught"); wicket(inn, "lbw"); wicket(inn, "stumped"); ball(inn, 1)`, "ball: the India innings is over"},
+		{`inn["over"]`, false},
+		{`ball(inn, -1)`, "ball: runs can't be negative, got -1"},
+		{`wicket(inn, "timed out")`, `wicket: unknown dismissal "timed out"`},
+		{`wicket(inn, "bowled", 1)`, "wicket: only a run out can complete runs or dismiss the non-striker"},
+		{`wicket(inn, "run out", 0, "keeper")`, "end for `wicket` must be \"striker\" or \"nonStriker\", got keeper"},
+		{`ball(inn)`, "wrong number of arguments. got=1, want=2"},
+		{`ball(1, 1)`, "argument to `ball` must be INNINGS, got INTEGER"},
+		{`ball(inn, "four")`, "runs for `ball` must be INTEGER, got STRING"},
+		{`inn["captain"]`, "innings has no captain"},
+	}
+
+	for _, tt := range tests {
+		input := inningsSetup + tt.input
+		evaluated := testEval(input)
+		if expected, ok := tt.expected.(bool); ok {
+			testBooleanObject(t, evaluated, expected)
+			continue
+		}
+		testBuiltinResult(t, input, evaluated, tt.expected)
+	}
+}
+
+func TestInningsConstruction(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`innings("India", ["Rohit"])`, "innings: an innings needs at least 2 batters, got 1"},
+		{`innings("India", ["Rohit", "Rohit"])`, "innings: Rohit appears twice in the batting order"},
+		{`innings("India", ["Rohit", 1])`, "batters for `innings` must be STRING, got INTEGER"},
+		{`innings("India", ["Rohit", "Gill"], 0)`, "overs for `innings` must be a positive INTEGER, got 0"},
+		{`innings(1, [])`, "team for `innings` must be STRING, got INTEGER"},
+		{`innings("India")`, "wrong number of arguments. got=1, want=2 or 3"},
+	}
+
+	for _, tt := range tests {
+		testBuiltinResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestInningsOversLimit(t *testing.T) {
+	input := `player inn = innings("India", ["Rohit", "Gill"], 1);
+	ball(inn, 1); ball(inn, 1); ball(inn, 1); ball(inn, 1); ball(inn, 1); wide(inn); ball(inn, 1);
+	[inn["over"], inn["overs"], inn["runs"]]`
+
+	evaluated := testEval(input)
+	if evaluated.Inspect() != "[true, 1.0, 7]" {
+		t.Errorf("wrong innings after the last over. want=[true, 1.0, 7], got=%s", evaluated.Inspect())
+	}
+}
+
+func TestInningsFallOfWicketsAndPartnerships(t *testing.T) {
+	input := inningsSetup + `
+	ball(inn, 4); ball(inn, 1); ball(inn, 2); wicket(inn, "caught");
+	ball(inn, 6); wide(inn); ball(inn, 0);
+	[inn["fallOfWickets"], inn["partnerships"]]`
+
+	evaluated, ok := testEval(input).(*object.Array)
+	if !ok {
+		t.Fatalf("object is not Array. got=%T", testEval(input))
+	}
+
+	fall := `[{wicket: 1, score: 7, batter: Gill, overs: 0.4}]`
+	if evaluated.Elements[0].Inspect() != fall {
+		t.Errorf("wrong fall of wickets. want=%s, got=%s", fall, evaluated.Elements[0].Inspect())
+	}
+	partnerships := `[{batters: [Rohit, Gill], runs: 7, balls: 4}, {batters: [Kohli, Rohit], runs: 7, balls: 2}]`
+	if evaluated.Elements[1].Inspect() != partnerships {
+		t.Errorf("wrong partnerships. want=%s, got=%s", partnerships, evaluated.Elements[1].Inspect())
+	}
+}
+
+func TestInningsScorecard(t *testing.T) {
+	input := inningsSetup + `
+	ball(inn, 4); ball(inn, 1); legBye(inn, 1); wicket(inn, "bowled");
+	noBall(inn, 6); wide(inn); ball(inn, 0); bye(inn, 2);
+	inn`
+
+	scorecard := testEval(input).Inspect()
+	for _, line := range []string{
+		"India innings",
+		"Rohit                bowled           5     3    1    0   166.67",
+		"Gill                 not out          0*    1    0    0     0.00",
+		"Kohli                not out          6*    3    0    1   200.00",
+		"Extras               (b 2, lb 1, w 1, nb 1)       5",
+		"Total                (1 wkts, 1.0 ov)            16",
+		"Did not bat: Iyer",
+		"Fall of wickets: 1-6 (Rohit, 0.4 ov)",
+		"Partnerships: 1st wkt 6 (4) Rohit & Gill, 2nd wkt 10* (2) Kohli & Gill",
+	} {
+		if !strings.Contains(scorecard, line) {
+			t.Errorf("scorecard is missing %q. got=\n%s", line, scorecard)
+		}
+	}
+}
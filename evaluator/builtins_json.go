@@ -0,0 +1,195 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var jsonBuiltins = map[string]*object.Builtin{
+	"toJSON": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return newMisfield("wrong number of arguments. got=%d, want=1 or 2", len(args))
+			}
+
+			var out bytes.Buffer
+			if misfield := encodeJSON(&out, args[0]); misfield != nil {
+				return misfield
+			}
+			if len(args) == 1 {
+				return &object.String{Value: out.String()}
+			}
+
+			indent, ok := args[1].(*object.String)
+			if !ok {
+				return newMisfield("indent for `toJSON` must be STRING, got %s", args[1].Type())
+			}
+			var indented bytes.Buffer
+			json.Indent(&indented, out.Bytes(), "", indent.Value)
+			return &object.String{Value: indented.String()}
+		},
+	},
+	"fromJSON": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newMisfield("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			str, ok := args[0].(*object.String)
+			if !ok {
+				return newMisfield("argument to `fromJSON` must be STRING, got %s", args[0].Type())
+			}
+			return decodeJSON(str.Value)
+		},
+	},
+}
+
+// encodeJSON writes obj as compact JSON. Hash keys keep their order.
+func encodeJSON(out *bytes.Buffer, obj object.Object) *object.Misfield {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		out.WriteString(strconv.FormatInt(obj.Value, 10))
+	case *object.Float:
+		if math.IsInf(obj.Value, 0) || math.IsNaN(obj.Value) {
+			return newMisfield("cannot encode %s as JSON", obj.Inspect())
+		}
+		out.WriteString(strconv.FormatFloat(obj.Value, 'g', -1, 64))
+	case *object.String:
+		encodeJSONString(out, obj.Value)
+	case *object.Boolean:
+		out.WriteString(strconv.FormatBool(obj.Value))
+	case *object.DeadBallNull:
+		out.WriteString("null")
+	case *object.Array:
+		out.WriteString("[")
+		for i, e := range obj.Elements {
+			if i > 0 {
+				out.WriteString(",")
+			}
+			if misfield := encodeJSON(out, e); misfield != nil {
+				return misfield
+			}
+		}
+		out.WriteString("]")
+	case *object.Hash:
+		out.WriteString("{")
+		for i, key := range obj.Keys {
+			if i > 0 {
+				out.WriteString(",")
+			}
+			encodeJSONString(out, key)
+			out.WriteString(":")
+			if misfield := encodeJSON(out, obj.Pairs[key]); misfield != nil {
+				return misfield
+			}
+		}
+		out.WriteString("}")
+	default:
+		return newMisfield("cannot encode %s as JSON", obj.Type())
+	}
+	return nil
+}
+
+func encodeJSONString(out *bytes.Buffer, s string) {
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	out.Truncate(out.Len() - 1) // Encode ends every value with a newline
+}
+
+// decodeJSON parses a single JSON value. Objects become hashes in source
+// order, whole numbers that fit become integers and other numbers floats.
+//
+// The input is validated with json.Unmarshal first since its syntax errors
+// pinpoint the offending byte; the token stream used to keep key order
+// reports them less precisely.
+func decodeJSON(input string) object.Object {
+	var v interface{}
+	if err := json.Unmarshal([]byte(input), &v); err != nil {
+		return jsonMisfield(input, err)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(input))
+	dec.UseNumber()
+
+	value, err := decodeJSONValue(dec)
+	if err != nil {
+		return jsonMisfield(input, err)
+	}
+	return value
+}
+
+func decodeJSONValue(dec *json.Decoder) (object.Object, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok := tok.(type) {
+	case nil:
+		return DEAD_BALL, nil
+	case bool:
+		return nativeBoolToBooleanObject(tok), nil
+	case string:
+		return &object.String{Value: tok}, nil
+	case json.Number:
+		if integer, err := strconv.ParseInt(string(tok), 10, 64); err == nil {
+			return &object.Integer{Value: integer}, nil
+		}
+		float, err := strconv.ParseFloat(string(tok), 64)
+		if err != nil {
+			return nil, err
+		}
+		return &object.Float{Value: float}, nil
+	case json.Delim:
+		if tok == '[' {
+			elements := []object.Object{}
+			for dec.More() {
+				e, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, e)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return &object.Array{Elements: elements}, nil
+		}
+
+		hash := object.NewHash()
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSONValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			hash.Set(key.(string), value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return hash, nil
+	}
+	return nil, errors.New("unexpected JSON token")
+}
+
+// jsonMisfield reports a decoding error with the byte offset it was found
+// at: the offending byte, or the length of the input if it ended early.
+func jsonMisfield(input string, err error) *object.Misfield {
+	var syntax *json.SyntaxError
+	if !errors.As(err, &syntax) {
+		return newMisfield("invalid JSON: %s", err)
+	}
+	if syntax.Offset >= int64(len(input)) && strings.HasPrefix(syntax.Error(), "unexpected end") {
+		return newMisfield("invalid JSON at offset %d: unexpected end of input", len(input))
+	}
+	return newMisfield("invalid JSON at offset %d: %s", syntax.Offset-1, syntax.Error())
+}
@@ -0,0 +1,128 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"testing"
+)
+
+func TestToJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`toJSON(1)`, `1`},
+		{`toJSON(-float(5) / 2)`, `-2.5`},
+		{`toJSON("six")`, `"six"`},
+		{`toJSON("<b>&</b>")`, `"<b>&</b>"`},
+		{`toJSON(notout)`, `true`},
+		{`toJSON(out)`, `false`},
+		{`toJSON(appeal (out) { 1 })`, `null`},
+		{`toJSON([1, "a", [notout]])`, `[1,"a",[true]]`},
+		{`toJSON({"runs": 82, "balls": 53, "out": out})`, `{"runs":82,"balls":53,"out":false}`},
+		{`toJSON({})`, `{}`},
+		{`toJSON({"a": [1, 2]}, "  ")`, "{\n  \"a\": [\n    1,\n    2\n  ]\n}"},
+		{`toJSON(field(x) { x })`, "cannot encode FIELD as JSON"},
+		{`toJSON([1, len])`, "cannot encode BUILTIN as JSON"},
+		{`toJSON(float(1) / 0)`, "cannot encode +Inf as JSON"},
+		{`toJSON(1, 2)`, "indent for `toJSON` must be STRING, got INTEGER"},
+		{`toJSON()`, "wrong number of arguments. got=0, want=1 or 2"},
+	}
+
+	for _, tt := range tests {
+		testBuiltinResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`1`, `1`},
+		{` -12 `, `-12`},
+		{`2.5`, `2.5`},
+		{`1e3`, `1000.0`},
+		{`99999999999999999999`, `100000000000000000000.0`},
+		{`null`, `deadball`},
+		{`true`, `true`},
+		{`[1, [2, null], false]`, `[1, [2, deadball], false]`},
+		{`{"batter": "Kohli", "runs": 82, "shots": {"4": 6, "6": 4}}`, `{batter: Kohli, runs: 82, shots: {4: 6, 6: 4}}`},
+		{`{"b": 1, "a": 2, "b": 3}`, `{b: 3, a: 2}`},
+		{`"café"`, `café`},
+	}
+
+	for _, tt := range tests {
+		evaluated := decodeJSON(tt.input)
+		if isMisfield(evaluated) {
+			t.Errorf("fromJSON(%s) misfielded: %s", tt.input, evaluated.Inspect())
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("fromJSON(%s) wrong. want=%s, got=%s", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+
+	testBooleanObject(t, decodeJSON(`true`), true)
+	testBooleanObject(t, decodeJSON(`false`), false)
+	testNullObject(t, decodeJSON(`null`))
+	testIntegerObject(t, decodeJSON(`42`), 42)
+}
+
+func TestFromJSONMisfields(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{``, "invalid JSON at offset 0: unexpected end of input"},
+		{`[1, 2`, "invalid JSON at offset 5: unexpected end of input"},
+		{`tru`, "invalid JSON at offset 3: unexpected end of input"},
+		{`[1,]`, "invalid JSON at offset 3: invalid character ']' looking for beginning of value"},
+		{`{"a" 1}`, "invalid JSON at offset 5: invalid character '1' after object key"},
+		{`1 2`, "invalid JSON at offset 2: invalid character '2' after top-level value"},
+		{`{"a": 1}}`, "invalid JSON at offset 8: invalid character '}' after top-level value"},
+		{`{"a": tru}`, "invalid JSON at offset 9: invalid character '}' in literal true (expecting 'e')"},
+	}
+
+	for _, tt := range tests {
+		misfield, ok := decodeJSON(tt.input).(*object.Misfield)
+		if !ok {
+			t.Errorf("fromJSON(%q) did not misfield", tt.input)
+			continue
+		}
+		if misfield.Message != tt.expected {
+			t.Errorf("fromJSON(%q) wrong message.\nwant=%q\ngot= %q", tt.input, tt.expected, misfield.Message)
+		}
+	}
+}
+
+func TestJSONRoundTripsThroughFiles(t *testing.T) {
+	root := writeModules(t, map[string]string{
+		"feed.json": `{"match": "IND v AUS", "overs": [[1, 4, 0, 6, 1, 1], [0, 0, 4]], "result": null}`,
+	})
+
+	input := `
+	player feed = fromJSON(readFile("feed.json"));
+	player total = reduce(map(feed["overs"], field(over) { reduce(over, field(a, b) { a + b }) }), field(a, b) { a + b });
+	writeFile("out.json", toJSON({"match": feed["match"], "total": total, "result": feed["result"]}));
+	readFile("out.json")
+	`
+
+	evaluated := testEvalWithFiles(input, root, root)
+	testBuiltinResult(t, input, evaluated, `{"match":"IND v AUS","total":17,"result":null}`)
+}
+
+func TestFromJSONBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`fromJSON("[1, 2, 3]")[2]`, 3},
+		{`len(fromJSON("[1, 2, 3]"))`, 3},
+		{`fromJSON(5)`, "argument to `fromJSON` must be STRING, got INTEGER"},
+		{`fromJSON("[1,")`, "invalid JSON at offset 3: unexpected end of input"},
+	}
+
+	for _, tt := range tests {
+		testBuiltinResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
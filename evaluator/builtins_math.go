@@ -0,0 +1,268 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"math"
+)
+
+// constants are looked up after builtins, so a program can shadow them.
+var constants = map[string]object.Object{
+	"PI":             &object.Float{Value: math.Pi},
+	"E":              &object.Float{Value: math.E},
+	"BALLS_PER_OVER": &object.Integer{Value: object.BallsPerOver},
+}
+
+// The math builtins accept integers and floats alike. Results stay integers
+// where that is exact (abs, min, max, pow with a non-negative integer
+// exponent, floor, ceil, round to 0 decimals, clamp, gcd) and are floats
+// otherwise.
+var mathBuiltins = map[string]*object.Builtin{
+	"abs": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			numbers, misfield := numberArguments("abs", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			switch n := numbers[0].(type) {
+			case *object.Integer:
+				if n.Value == math.MinInt64 {
+					return newMisfield("abs(%d) is too large for an INTEGER, use abs(float(%d))", n.Value, n.Value)
+				}
+				if n.Value < 0 {
+					return &object.Integer{Value: -n.Value}
+				}
+				return n
+			default:
+				return &object.Float{Value: math.Abs(toFloat(n))}
+			}
+		},
+	},
+	"min": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			return extreme("min", args, func(a, b float64) bool { return a < b })
+		},
+	},
+	"max": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			return extreme("max", args, func(a, b float64) bool { return a > b })
+		},
+	},
+	"pow": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			numbers, misfield := numberArguments("pow", 2, args)
+			if misfield != nil {
+				return misfield
+			}
+			base, baseIsInt := numbers[0].(*object.Integer)
+			exp, expIsInt := numbers[1].(*object.Integer)
+			if baseIsInt && expIsInt && exp.Value >= 0 {
+				result, ok := integerPow(base.Value, exp.Value)
+				if !ok {
+					return newMisfield("pow(%d, %d) is too large for an INTEGER, use pow(float(%d), %d)",
+						base.Value, exp.Value, base.Value, exp.Value)
+				}
+				return &object.Integer{Value: result}
+			}
+			return &object.Float{Value: math.Pow(toFloat(numbers[0]), toFloat(numbers[1]))}
+		},
+	},
+	"sqrt": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			numbers, misfield := numberArguments("sqrt", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			value := toFloat(numbers[0])
+			if value < 0 {
+				return newMisfield("square root of negative number %s", numbers[0].Inspect())
+			}
+			return &object.Float{Value: math.Sqrt(value)}
+		},
+	},
+	"floor": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			return roundWith("floor", args, math.Floor)
+		},
+	},
+	"ceil": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			return roundWith("ceil", args, math.Ceil)
+		},
+	},
+	"round": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return roundWith("round", args, math.Round)
+			}
+			numbers, misfield := numberArguments("round", 1, args[:1])
+			if misfield != nil {
+				return misfield
+			}
+			places, ok := args[1].(*object.Integer)
+			if !ok || places.Value < 0 {
+				return newMisfield("decimal places for `round` must be a non-negative INTEGER, got %s", args[1].Inspect())
+			}
+			if integer, ok := numbers[0].(*object.Integer); ok {
+				return integer
+			}
+			if places.Value == 0 {
+				return roundWith("round", numbers, math.Round)
+			}
+			scale := math.Pow(10, float64(places.Value))
+			return &object.Float{Value: math.Round(toFloat(numbers[0])*scale) / scale}
+		},
+	},
+	"clamp": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			numbers, misfield := numberArguments("clamp", 3, args)
+			if misfield != nil {
+				return misfield
+			}
+			value, low, high := numbers[0], numbers[1], numbers[2]
+			if toFloat(low) > toFloat(high) {
+				return newMisfield("clamp bounds are reversed: %s > %s", low.Inspect(), high.Inspect())
+			}
+			switch {
+			case toFloat(value) < toFloat(low):
+				return low
+			case toFloat(value) > toFloat(high):
+				return high
+			default:
+				return value
+			}
+		},
+	},
+	"gcd": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) < 2 {
+				return newMisfield("wrong number of arguments. got=%d, want at least 2", len(args))
+			}
+			// unsigned, so that the magnitude of math.MinInt64 fits
+			result := uint64(0)
+			for _, arg := range args {
+				integer, ok := arg.(*object.Integer)
+				if !ok {
+					return newMisfield("argument to `gcd` must be INTEGER, got %s", arg.Type())
+				}
+				a, b := result, uint64(integer.Value)
+				if integer.Value < 0 {
+					b = -b
+				}
+				for b != 0 {
+					a, b = b, a%b
+				}
+				result = a
+			}
+			if result > math.MaxInt64 {
+				return newMisfield("gcd %d is too large for an INTEGER", result)
+			}
+			return &object.Integer{Value: int64(result)}
+		},
+	},
+	"float": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			numbers, misfield := numberArguments("float", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			return &object.Float{Value: toFloat(numbers[0])}
+		},
+	},
+	"int": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			return roundWith("int", args, math.Trunc)
+		},
+	},
+}
+
+// numberArguments checks a builtin got want arguments, all integers or
+// floats.
+func numberArguments(name string, want int, args []object.Object) ([]object.Object, *object.Misfield) {
+	if len(args) != want {
+		return nil, newMisfield("wrong number of arguments. got=%d, want=%d", len(args), want)
+	}
+	for _, arg := range args {
+		if !isNumber(arg) {
+			return nil, newMisfield("argument to `%s` must be INTEGER or FLOAT, got %s", name, arg.Type())
+		}
+	}
+	return args, nil
+}
+
+// integerPow raises base to exp by squaring, and reports false if the
+// result doesn't fit in an int64.
+func integerPow(base, exp int64) (int64, bool) {
+	result := int64(1)
+	var ok bool
+	for exp > 0 {
+		if exp&1 == 1 {
+			if result, ok = multiplyIntegers(result, base); !ok {
+				return 0, false
+			}
+		}
+		exp >>= 1
+		if exp > 0 {
+			if base, ok = multiplyIntegers(base, base); !ok {
+				return 0, false
+			}
+		}
+	}
+	return result, true
+}
+
+func multiplyIntegers(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	product := a * b
+	if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+	return product, true
+}
+
+// roundWith applies round to a float, returning an integer. Integers are
+// returned as they are.
+func roundWith(name string, args []object.Object, round func(float64) float64) object.Object {
+	numbers, misfield := numberArguments(name, 1, args)
+	if misfield != nil {
+		return misfield
+	}
+	if integer, ok := numbers[0].(*object.Integer); ok {
+		return integer
+	}
+	value := round(toFloat(numbers[0]))
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return newMisfield("cannot convert %s to INTEGER", numbers[0].Inspect())
+	}
+	// -2^63 and 2^63 are exact as floats; the int64 range is [-2^63, 2^63)
+	if value < math.MinInt64 || value >= -math.MinInt64 {
+		return newMisfield("%s(%s) is too large for an INTEGER", name, numbers[0].Inspect())
+	}
+	return &object.Integer{Value: int64(value)}
+}
+
+// extreme implements min and max: it takes numbers either as arguments or
+// as a single array, and returns the one better says wins.
+func extreme(name string, args []object.Object, better func(a, b float64) bool) object.Object {
+	values := args
+	if len(args) == 1 {
+		if arr, ok := args[0].(*object.Array); ok {
+			values = arr.Elements
+		}
+	}
+	if len(values) == 0 {
+		return newMisfield("`%s` needs at least one number", name)
+	}
+
+	best := values[0]
+	for _, v := range values {
+		if !isNumber(v) {
+			return newMisfield("argument to `%s` must be INTEGER or FLOAT, got %s", name, v.Type())
+		}
+		if better(toFloat(v), toFloat(best)) {
+			best = v
+		}
+	}
+	return best
+}
@@ -0,0 +1,141 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"math"
+	"testing"
+)
+
+func TestFloatArithmetic(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`float(7) / 2`, 3.5},
+		{`7 / 2`, 3},
+		{`1 + float(2)`, 3.0},
+		{`float(1) - 3`, -2.0},
+		{`-float(2) * 3`, -6.0},
+		{`float(1) / 4 < 1`, true},
+		{`float(2) == 2`, true},
+		{`float(2) != 2`, false},
+		{`float(1) + "a"`, "player type mismatch: FLOAT + STRING"},
+		{`float(1) + notout`, "player type mismatch: FLOAT + BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		testMathResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestFloatInspect(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{2, "2.0"},
+		{-0.5, "-0.5"},
+		{133.25, "133.25"},
+		{math.Inf(1), "+Inf"},
+	}
+
+	for _, tt := range tests {
+		if got := (&object.Float{Value: tt.value}).Inspect(); got != tt.expected {
+			t.Errorf("wrong Inspect for %v. want=%q, got=%q", tt.value, tt.expected, got)
+		}
+	}
+}
+
+func TestMathBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`abs(-5)`, 5},
+		{`abs(5)`, 5},
+		{`abs(-float(5) / 2)`, 2.5},
+		{`abs("5")`, "argument to `abs` must be INTEGER or FLOAT, got STRING"},
+		{`abs(-9223372036854775807)`, 9223372036854775807},
+		{`abs(-9223372036854775807 - 1)`, "abs(-9223372036854775808) is too large for an INTEGER, use abs(float(-9223372036854775808))"},
+		{`min(3, 1, 2)`, 1},
+		{`max(3, 1, 2)`, 3},
+		{`max([4, 6, 1])`, 6},
+		{`min(2, float(3) / 2)`, 1.5},
+		{`max(7)`, 7},
+		{`max([])`, "`max` needs at least one number"},
+		{`min(1, "a")`, "argument to `min` must be INTEGER or FLOAT, got STRING"},
+		{`pow(2, 10)`, 1024},
+		{`pow(2, 0)`, 1},
+		{`pow(2, -1)`, 0.5},
+		{`pow(2, 62)`, 4611686018427387904},
+		{`pow(-2, 63)`, -9223372036854775808},
+		{`pow(3, 39)`, 4052555153018976267},
+		{`pow(1, 1000000000000)`, 1},
+		{`pow(-1, 1000000000001)`, -1},
+		{`pow(0, 1000000000000)`, 0},
+		{`pow(2, 63)`, "pow(2, 63) is too large for an INTEGER, use pow(float(2), 63)"},
+		{`pow(-2, 64)`, "pow(-2, 64) is too large for an INTEGER, use pow(float(-2), 64)"},
+		{`pow(10, 19)`, "pow(10, 19) is too large for an INTEGER, use pow(float(10), 19)"},
+		{`pow(float(2), 63)`, 9223372036854775808.0},
+		{`pow(float(9), float(1) / 2)`, 3.0},
+		{`sqrt(16)`, 4.0},
+		{`sqrt(-1)`, "square root of negative number -1"},
+		{`floor(float(7) / 2)`, 3},
+		{`floor(-float(7) / 2)`, -4},
+		{`ceil(float(7) / 2)`, 4},
+		{`floor(5)`, 5},
+		{`round(float(5) / 2)`, 3},
+		{`round(float(200) / 3, 2)`, 66.67},
+		{`round(float(200) / 3, 0)`, 67},
+		{`round(7, 2)`, 7},
+		{`round(float(1), -1)`, "decimal places for `round` must be a non-negative INTEGER, got -1"},
+		{`clamp(7, 0, 6)`, 6},
+		{`clamp(-1, 0, 6)`, 0},
+		{`clamp(3, 0, 6)`, 3},
+		{`clamp(3, 6, 0)`, "clamp bounds are reversed: 6 > 0"},
+		{`gcd(12, 18)`, 6},
+		{`gcd(12, -18, 8)`, 2},
+		{`gcd(0, 5)`, 5},
+		{`gcd(-9223372036854775807 - 1, 6)`, 2},
+		{`gcd(-9223372036854775807 - 1, 0)`, "gcd 9223372036854775808 is too large for an INTEGER"},
+		{`gcd(12)`, "wrong number of arguments. got=1, want at least 2"},
+		{`gcd(12, float(3))`, "argument to `gcd` must be INTEGER, got FLOAT"},
+		{`int(float(7) / 2)`, 3},
+		{`int(-float(7) / 2)`, -3},
+		{`int(float(9223372036854775807) * float(4))`, "int(36893488147419103000.0) is too large for an INTEGER"},
+		{`int(float(9223372036854775807))`, "int(9223372036854776000.0) is too large for an INTEGER"},
+		{`int(-float(9223372036854775807) - float(1))`, -9223372036854775808},
+		{`int(float(-9223372036854775807) * float(2))`, "int(-18446744073709552000.0) is too large for an INTEGER"},
+		{`round(float(9223372036854775807) * float(4))`, "round(36893488147419103000.0) is too large for an INTEGER"},
+		{`floor(-float(9223372036854775807) * float(4))`, "floor(-36893488147419103000.0) is too large for an INTEGER"},
+		{`float(3)`, 3.0},
+		{`PI > 3`, true},
+		{`round(E, 3)`, 2.718},
+		{`20 * BALLS_PER_OVER`, 120},
+		{`player PI = 3; PI`, 3},
+	}
+
+	for _, tt := range tests {
+		testMathResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func testMathResult(t *testing.T, input string, evaluated object.Object, expected interface{}) {
+	t.Helper()
+
+	switch expected := expected.(type) {
+	case float64:
+		result, ok := evaluated.(*object.Float)
+		if !ok {
+			t.Errorf("%s: object is not Float. got=%T (%+v)", input, evaluated, evaluated)
+			return
+		}
+		if math.Abs(result.Value-expected) > 1e-9 {
+			t.Errorf("%s: wrong value. want=%v, got=%v", input, expected, result.Value)
+		}
+	case bool:
+		testBooleanObject(t, evaluated, expected)
+	default:
+		testBuiltinResult(t, input, evaluated, expected)
+	}
+}
@@ -0,0 +1,140 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var outputBuiltins = map[string]*object.Builtin{
+	"print": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			io.WriteString(ctx.Env.Runtime().Out, inspectAll(args))
+			return DEAD_BALL
+		},
+	},
+	"println": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			io.WriteString(ctx.Env.Runtime().Out, inspectAll(args)+"\n")
+			return DEAD_BALL
+		},
+	},
+	"printf": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			formatted := sprintf("printf", args)
+			if isMisfield(formatted) {
+				return formatted
+			}
+			io.WriteString(ctx.Env.Runtime().Out, formatted.(*object.String).Value)
+			return DEAD_BALL
+		},
+	},
+	"sprintf": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			return sprintf("sprintf", args)
+		},
+	},
+}
+
+// inspectAll joins the values the way print shows them, separated by spaces.
+func inspectAll(args []object.Object) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = arg.Inspect()
+	}
+	return strings.Join(parts, " ")
+}
+
+// sprintf formats args[1:] according to the format string args[0].
+//
+// A verb is %[flags][width][.precision]verb with the flags '-' (pad on the
+// right), '0' (pad with zeros) and '+' (always show the sign). The verbs are
+// %d for integers, %f for integers and floats, %s and %v for any value as print shows it, %q for a
+// quoted string, %t for booleans and %% for a literal percent sign.
+func sprintf(name string, args []object.Object) object.Object {
+	if len(args) == 0 {
+		return newMisfield("wrong number of arguments. got=0, want at least 1")
+	}
+	format, ok := args[0].(*object.String)
+	if !ok {
+		return newMisfield("format for `%s` must be STRING, got %s", name, args[0].Type())
+	}
+	values := args[1:]
+
+	var out strings.Builder
+	used := 0
+	runes := []rune(format.Value)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '%' {
+			out.WriteRune(runes[i])
+			continue
+		}
+
+		start := i
+		i++
+		for i < len(runes) && strings.ContainsRune("-0+", runes[i]) {
+			i++
+		}
+		for i < len(runes) && (runes[i] >= '0' && runes[i] <= '9' || runes[i] == '.') {
+			i++
+		}
+		if i == len(runes) {
+			return newMisfield("%s: format ends in the middle of a verb: %q", name, string(runes[start:]))
+		}
+
+		spec, verb := string(runes[start:i]), runes[i]
+		if verb == '%' {
+			out.WriteRune('%')
+			continue
+		}
+		if used == len(values) {
+			return newMisfield("%s: missing argument for %s%c", name, spec, verb)
+		}
+		value := values[used]
+		used++
+
+		formatted, misfield := formatVerb(name, spec, verb, value)
+		if misfield != nil {
+			return misfield
+		}
+		out.WriteString(formatted)
+	}
+
+	if used != len(values) {
+		return newMisfield("%s: too many arguments. got=%d, used=%d", name, len(values), used)
+	}
+	return &object.String{Value: out.String()}
+}
+
+func formatVerb(name, spec string, verb rune, value object.Object) (string, *object.Misfield) {
+	switch verb {
+	case 'd':
+		integer, ok := value.(*object.Integer)
+		if !ok {
+			return "", newMisfield("%s: %%d needs INTEGER, got %s", name, value.Type())
+		}
+		return fmt.Sprintf(spec+"d", integer.Value), nil
+	case 'f':
+		if !isNumber(value) {
+			return "", newMisfield("%s: %%f needs INTEGER or FLOAT, got %s", name, value.Type())
+		}
+		return fmt.Sprintf(spec+"f", toFloat(value)), nil
+	case 's', 'v':
+		return fmt.Sprintf(spec+"s", value.Inspect()), nil
+	case 'q':
+		str, ok := value.(*object.String)
+		if !ok {
+			return "", newMisfield("%s: %%q needs STRING, got %s", name, value.Type())
+		}
+		return fmt.Sprintf(spec+"q", str.Value), nil
+	case 't':
+		boolean, ok := value.(*object.Boolean)
+		if !ok {
+			return "", newMisfield("%s: %%t needs BOOLEAN, got %s", name, value.Type())
+		}
+		return fmt.Sprintf(spec+"s", boolean.Inspect()), nil
+	default:
+		return "", newMisfield("%s: unknown verb %s%c", name, spec, verb)
+	}
+}
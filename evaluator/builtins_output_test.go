@@ -0,0 +1,90 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"bytes"
+	"testing"
+)
+
+func testEvalOutput(input string) (object.Object, string) {
+	var out bytes.Buffer
+	runtime := object.NewRuntime()
+	runtime.Out = &out
+
+	evaluated := testEvalIn(input, runtime, "")
+	return evaluated, out.String()
+}
+
+func TestPrintBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`print("four")`, "four"},
+		{`print("four", 4, [4, "four"])`, "four 4 [4, four]"},
+		{`print()`, ""},
+		{`println("six")`, "six\n"},
+		{`println()`, "\n"},
+		{`println("a"); println("b")`, "a\nb\n"},
+		{`printf("%s scored %d", "Dhoni", 183); println()`, "Dhoni scored 183\n"},
+		{`gambhir("a", "b"); 1`, "Interviewer: a or b\n"},
+		{`rohit("Dhoni")`, "Reporter: Dhoni ke birthday ke baare mei kuch boliye.\n"},
+	}
+
+	for _, tt := range tests {
+		evaluated, output := testEvalOutput(tt.input)
+		if isMisfield(evaluated) {
+			t.Errorf("%s: unexpected misfield: %s", tt.input, evaluated.Inspect())
+			continue
+		}
+		if output != tt.expected {
+			t.Errorf("%s: wrong output. want=%q, got=%q", tt.input, tt.expected, output)
+		}
+	}
+}
+
+func TestPrintReturnsDeadBall(t *testing.T) {
+	for _, input := range []string{`print(1)`, `println(1)`, `printf("%d", 1)`} {
+		evaluated, _ := testEvalOutput(input)
+		testNullObject(t, evaluated)
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`sprintf("no verbs")`, "no verbs"},
+		{`sprintf("%d/%d", 183, 2)`, "183/2"},
+		{`sprintf("%5d|", 42)`, "   42|"},
+		{`sprintf("%-5d|", 42)`, "42   |"},
+		{`sprintf("%05d", 42)`, "00042"},
+		{`sprintf("%+d", 42)`, "+42"},
+		{`sprintf("%-10s|%4s|", "Kohli", "82*")`, "Kohli     | 82*|"},
+		{`sprintf("%6s|", "héllo")`, " héllo|"},
+		{`sprintf("%.3s", "Tendulkar")`, "Ten"},
+		{`sprintf("%v %s", [1, 2], 7)`, "[1, 2] 7"},
+		{`sprintf("%.2f", 7 * 100 / float(6))`, "116.67"},
+		{`sprintf("%6.1f|", 42)`, "  42.0|"},
+		{`sprintf("%f", "x")`, "sprintf: %f needs INTEGER or FLOAT, got STRING"},
+		{`sprintf("%q", "no ball")`, `"no ball"`},
+		{`sprintf("%t", 1 < 2)`, "true"},
+		{`sprintf("100%%")`, "100%"},
+		{`sprintf(5)`, "format for `sprintf` must be STRING, got INTEGER"},
+		{`sprintf()`, "wrong number of arguments. got=0, want at least 1"},
+		{`sprintf("%d")`, "sprintf: missing argument for %d"},
+		{`sprintf("%d", "x")`, "sprintf: %d needs INTEGER, got STRING"},
+		{`sprintf("%q", 1)`, "sprintf: %q needs STRING, got INTEGER"},
+		{`sprintf("%t", 1)`, "sprintf: %t needs BOOLEAN, got INTEGER"},
+		{`sprintf("%x", 1)`, "sprintf: unknown verb %x"},
+		{`sprintf("%5", 1)`, `sprintf: format ends in the middle of a verb: "%5"`},
+		{`sprintf("%d", 1, 2)`, "sprintf: too many arguments. got=2, used=1"},
+		{`printf("%d")`, "printf: missing argument for %d"},
+	}
+
+	for _, tt := range tests {
+		evaluated, _ := testEvalOutput(tt.input)
+		testBuiltinResult(t, tt.input, evaluated, tt.expected)
+	}
+}
@@ -0,0 +1,66 @@
+package evaluator
+
+import "CricLang/object"
+
+// All randomness comes from the runtime's source, so seed(n) or the --seed
+// flag makes a whole run, gambhir included, reproducible.
+var randomBuiltins = map[string]*object.Builtin{
+	"seed": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newMisfield("wrong number of arguments. got=%d, want=1", len(args))
+			}
+			seed, ok := args[0].(*object.Integer)
+			if !ok {
+				return newMisfield("argument to `seed` must be INTEGER, got %s", args[0].Type())
+			}
+			ctx.Env.Runtime().Seed(seed.Value)
+			return DEAD_BALL
+		},
+	},
+	"random": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			rng := ctx.Env.Runtime().Rand
+			switch len(args) {
+			case 0:
+				return &object.Float{Value: rng.Float64()}
+			case 1:
+				n, ok := args[0].(*object.Integer)
+				if !ok {
+					return newMisfield("argument to `random` must be INTEGER, got %s", args[0].Type())
+				}
+				if n.Value <= 0 {
+					return newMisfield("argument to `random` must be positive, got %d", n.Value)
+				}
+				return &object.Integer{Value: rng.Int63n(n.Value)}
+			default:
+				return newMisfield("wrong number of arguments. got=%d, want=0 or 1", len(args))
+			}
+		},
+	},
+	"randomChoice": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			arr, misfield := arrayArgument("randomChoice", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			if len(arr.Elements) == 0 {
+				return newMisfield("randomChoice from an empty ARRAY")
+			}
+			return arr.Elements[ctx.Env.Runtime().Rand.Intn(len(arr.Elements))]
+		},
+	},
+	"shuffle": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			arr, misfield := arrayArgument("shuffle", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			shuffled := newArray(arr.Elements)
+			ctx.Env.Runtime().Rand.Shuffle(len(shuffled.Elements), func(i, j int) {
+				shuffled.Elements[i], shuffled.Elements[j] = shuffled.Elements[j], shuffled.Elements[i]
+			})
+			return shuffled
+		},
+	},
+}
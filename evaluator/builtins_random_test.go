@@ -0,0 +1,91 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"io"
+	"testing"
+)
+
+func testEvalSeeded(input string, seed int64) object.Object {
+	runtime := object.NewRuntime()
+	runtime.Seed(seed)
+	runtime.Out = io.Discard
+	return testEvalIn(input, runtime, "")
+}
+
+func TestRandomIsReproducible(t *testing.T) {
+	input := `[random(1000), random(), randomChoice([1, 2, 3, 4, 5]), shuffle([1, 2, 3, 4, 5]), gambhir("a", "b")]`
+
+	first := testEvalSeeded(input, 42).Inspect()
+	second := testEvalSeeded(input, 42).Inspect()
+	if first != second {
+		t.Errorf("same seed gave different results.\nfirst= %s\nsecond=%s", first, second)
+	}
+
+	other := testEvalSeeded(input, 43).Inspect()
+	if first == other {
+		t.Errorf("different seeds gave the same result: %s", first)
+	}
+}
+
+func TestSeedBuiltin(t *testing.T) {
+	input := `
+	seed(7);
+	player a = [random(100), random(100), random(100)];
+	seed(7);
+	player b = [random(100), random(100), random(100)];
+	[a, b]
+	`
+
+	result, ok := testEvalSeeded(input, 1).(*object.Array)
+	if !ok || len(result.Elements) != 2 {
+		t.Fatalf("unexpected result: %v", result)
+	}
+	if result.Elements[0].Inspect() != result.Elements[1].Inspect() {
+		t.Errorf("seed(7) did not replay. got %s and %s", result.Elements[0].Inspect(), result.Elements[1].Inspect())
+	}
+}
+
+func TestRandomBuiltins(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		n := testEvalSeeded(`random(6)`, seed)
+		integer, ok := n.(*object.Integer)
+		if !ok || integer.Value < 0 || integer.Value >= 6 {
+			t.Fatalf("random(6) out of range: %s", n.Inspect())
+		}
+
+		f := testEvalSeeded(`random()`, seed)
+		float, ok := f.(*object.Float)
+		if !ok || float.Value < 0 || float.Value >= 1 {
+			t.Fatalf("random() out of range: %s", f.Inspect())
+		}
+
+		choice := testEvalSeeded(`contains("0146W", randomChoice(["0", "1", "4", "6", "W"]))`, seed)
+		testBooleanObject(t, choice, true)
+
+		shuffled := testEvalSeeded(`player a = [1, 2, 3, 4]; player s = shuffle(a); [a, sort(s)]`, seed)
+		if shuffled.Inspect() != "[[1, 2, 3, 4], [1, 2, 3, 4]]" {
+			t.Fatalf("shuffle is not a permutation or changed its input: %s", shuffled.Inspect())
+		}
+	}
+}
+
+func TestRandomMisfields(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`seed("x")`, "argument to `seed` must be INTEGER, got STRING"},
+		{`seed()`, "wrong number of arguments. got=0, want=1"},
+		{`random(0)`, "argument to `random` must be positive, got 0"},
+		{`random("6")`, "argument to `random` must be INTEGER, got STRING"},
+		{`random(1, 2)`, "wrong number of arguments. got=2, want=0 or 1"},
+		{`randomChoice([])`, "randomChoice from an empty ARRAY"},
+		{`randomChoice(1)`, "argument to `randomChoice` must be ARRAY, got INTEGER"},
+		{`shuffle("abc")`, "argument to `shuffle` must be ARRAY, got STRING"},
+	}
+
+	for _, tt := range tests {
+		testBuiltinResult(t, tt.input, testEvalSeeded(tt.input, 1), tt.expected)
+	}
+}
@@ -0,0 +1,286 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"CricLang/sim"
+)
+
+// The simulation builtins draw from the runtime's random source, so seed(n)
+// replays the same matches.
+var simBuiltins = map[string]*object.Builtin{
+	"simulateInnings": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) < 3 || len(args) > 5 {
+				return newMisfield("wrong number of arguments. got=%d, want=3 to 5", len(args))
+			}
+			batting, misfield := simTeam("simulateInnings", args[0])
+			if misfield != nil {
+				return misfield
+			}
+			bowling, misfield := simTeam("simulateInnings", args[1])
+			if misfield != nil {
+				return misfield
+			}
+			limits := []int64{0, 0, 0}
+			for i, arg := range args[2:] {
+				n, ok := arg.(*object.Integer)
+				if !ok {
+					return newMisfield("overs, target and quota for `simulateInnings` must be INTEGER, got %s", arg.Type())
+				}
+				limits[i] = n.Value
+			}
+			overs, target, quota := limits[0], limits[1], limits[2]
+			if quota == 0 {
+				quota = (overs + 4) / 5
+			}
+
+			result, err := sim.SimulateInnings(ctx.Env.Runtime().Rand, batting, bowling, overs, quota, target)
+			if err != nil {
+				return newMisfield("simulateInnings: %s", err)
+			}
+			return simInningsObject(result)
+		},
+	},
+	"simulateMatch": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			match, misfield := hashArgument("simulateMatch", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			m, misfield := simMatch("simulateMatch", match)
+			if misfield != nil {
+				return misfield
+			}
+
+			result, err := sim.SimulateMatch(ctx.Env.Runtime().Rand, m)
+			if err != nil {
+				return newMisfield("simulateMatch: %s", err)
+			}
+			return simMatchObject(result)
+		},
+	},
+	"winProbability": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			match, misfield := hashArgument("winProbability", 2, args)
+			if misfield != nil {
+				return misfield
+			}
+			n, ok := args[1].(*object.Integer)
+			if !ok {
+				return newMisfield("simulations for `winProbability` must be INTEGER, got %s", args[1].Type())
+			}
+			m, misfield := simMatch("winProbability", match)
+			if misfield != nil {
+				return misfield
+			}
+
+			odds, err := sim.WinProbability(ctx.Env.Runtime().Rand, m, n.Value)
+			if err != nil {
+				return newMisfield("winProbability: %s", err)
+			}
+
+			out := object.NewHash()
+			wins := object.NewHash()
+			probability := object.NewHash()
+			for _, team := range m.Teams {
+				wins.Set(team.Name, &object.Integer{Value: odds.Wins[team.Name]})
+				probability.Set(team.Name, &object.Float{Value: float64(odds.Wins[team.Name]) / float64(odds.Simulations)})
+			}
+			out.Set("simulations", &object.Integer{Value: odds.Simulations})
+			out.Set("wins", wins)
+			out.Set("ties", &object.Integer{Value: odds.Ties})
+			out.Set("probability", probability)
+			return out
+		},
+	},
+}
+
+// simMatch reads {"teams": [first, second], "overs": 20, "quota": 4}.
+func simMatch(name string, match *object.Hash) (sim.Match, *object.Misfield) {
+	m := sim.Match{}
+	teams, ok := match.Get("teams")
+	array, isArray := teams.(*object.Array)
+	if !ok || !isArray || len(array.Elements) != 2 {
+		return m, newMisfield("%s: match teams must be an ARRAY of 2 teams", name)
+	}
+	for i, el := range array.Elements {
+		team, misfield := simTeam(name, el)
+		if misfield != nil {
+			return m, misfield
+		}
+		m.Teams[i] = team
+	}
+
+	for _, field := range []struct {
+		key      string
+		value    *int64
+		required bool
+	}{{"overs", &m.Overs, true}, {"quota", &m.Quota, false}} {
+		value, ok := match.Get(field.key)
+		if !ok {
+			if field.required {
+				return m, newMisfield("%s: match is missing %s", name, field.key)
+			}
+			continue
+		}
+		n, ok := value.(*object.Integer)
+		if !ok {
+			return m, newMisfield("%s: match %s must be INTEGER, got %s", name, field.key, value.Type())
+		}
+		*field.value = n.Value
+	}
+	return m, nil
+}
+
+// simTeam reads {"name": ..., "batters": [...], "bowlers": [...]}. A player
+// is a name, which uses the default weights, or {"name", "outcomes"}.
+func simTeam(name string, obj object.Object) (sim.Team, *object.Misfield) {
+	team := sim.Team{}
+	hash, ok := obj.(*object.Hash)
+	if !ok {
+		return team, newMisfield("team for `%s` must be HASH, got %s", name, obj.Type())
+	}
+	teamName, ok := hash.Get("name")
+	str, isString := teamName.(*object.String)
+	if !ok || !isString {
+		return team, newMisfield("%s: team name must be STRING", name)
+	}
+	team.Name = str.Value
+
+	var misfield *object.Misfield
+	if team.Batters, misfield = simPlayers(name, hash, "batters", team.Name); misfield != nil {
+		return team, misfield
+	}
+	if team.Bowlers, misfield = simPlayers(name, hash, "bowlers", team.Name); misfield != nil {
+		return team, misfield
+	}
+	return team, nil
+}
+
+func simPlayers(name string, team *object.Hash, key, teamName string) ([]sim.Player, *object.Misfield) {
+	list, ok := team.Get(key)
+	array, isArray := list.(*object.Array)
+	if !ok || !isArray {
+		return nil, newMisfield("%s: %s %s must be ARRAY", name, teamName, key)
+	}
+
+	players := []sim.Player{}
+	for _, el := range array.Elements {
+		switch el := el.(type) {
+		case *object.String:
+			players = append(players, sim.Player{Name: el.Value})
+		case *object.Hash:
+			playerName, ok := el.Get("name")
+			str, isString := playerName.(*object.String)
+			if !ok || !isString {
+				return nil, newMisfield("%s: each of %s %s needs a STRING name", name, teamName, key)
+			}
+			player := sim.Player{Name: str.Value}
+			if outcomes, ok := el.Get("outcomes"); ok {
+				weights, misfield := simWeights(name, str.Value, outcomes)
+				if misfield != nil {
+					return nil, misfield
+				}
+				player.Weights = weights
+			}
+			players = append(players, player)
+		default:
+			return nil, newMisfield("%s: %s %s must be STRING or HASH, got %s", name, teamName, key, el.Type())
+		}
+	}
+	return players, nil
+}
+
+// simWeights reads outcome weights from a hash keyed by outcome, where a
+// missing outcome never happens, or an array in sim.Outcomes order.
+func simWeights(name, player string, outcomes object.Object) ([]float64, *object.Misfield) {
+	weights := make([]float64, len(sim.Outcomes))
+	switch outcomes := outcomes.(type) {
+	case *object.Hash:
+		index := map[string]int{}
+		for i, outcome := range sim.Outcomes {
+			index[outcome] = i
+		}
+		for _, key := range outcomes.Keys {
+			i, ok := index[key]
+			if !ok {
+				return nil, newMisfield("%s: unknown outcome %q for %s", name, key, player)
+			}
+			if !isNumber(outcomes.Pairs[key]) {
+				return nil, newMisfield("%s: weights for %s must be INTEGER or FLOAT, got %s", name, player, outcomes.Pairs[key].Type())
+			}
+			weights[i] = toFloat(outcomes.Pairs[key])
+		}
+	case *object.Array:
+		if len(outcomes.Elements) != len(sim.Outcomes) {
+			return nil, newMisfield("%s: %s needs %d outcome weights, got %d", name, player, len(sim.Outcomes), len(outcomes.Elements))
+		}
+		for i, el := range outcomes.Elements {
+			if !isNumber(el) {
+				return nil, newMisfield("%s: weights for %s must be INTEGER or FLOAT, got %s", name, player, el.Type())
+			}
+			weights[i] = toFloat(el)
+		}
+	default:
+		return nil, newMisfield("%s: outcomes for %s must be HASH or ARRAY, got %s", name, player, outcomes.Type())
+	}
+	return weights, nil
+}
+
+func simInningsObject(result *sim.InningsResult) object.Object {
+	inn := result.Innings
+	out := object.NewHash()
+	out.Set("team", &object.String{Value: inn.Team})
+	out.Set("runs", &object.Integer{Value: inn.Total})
+	out.Set("wickets", &object.Integer{Value: inn.Wickets})
+	out.Set("overs", &object.String{Value: inn.Overs()})
+	if result.Target > 0 {
+		out.Set("target", &object.Integer{Value: result.Target})
+	}
+	out.Set("scorecard", inn)
+
+	bowling := []object.Object{}
+	for _, f := range result.Bowling {
+		figures := object.NewHash()
+		figures.Set("name", &object.String{Value: f.Name})
+		figures.Set("overs", &object.String{Value: object.FormatOvers(f.Balls)})
+		figures.Set("runs", &object.Integer{Value: f.Runs})
+		figures.Set("wickets", &object.Integer{Value: f.Wickets})
+		bowling = append(bowling, figures)
+	}
+	out.Set("bowling", &object.Array{Elements: bowling})
+
+	balls := make([]object.Object, len(result.Deliveries))
+	for i, d := range result.Deliveries {
+		ball := object.NewHash()
+		ball.Set("ball", &object.String{Value: d.Label})
+		ball.Set("bowler", &object.String{Value: d.Bowler})
+		ball.Set("batter", &object.String{Value: d.Batter})
+		ball.Set("outcome", &object.String{Value: d.Outcome})
+		ball.Set("runs", &object.Integer{Value: d.Runs})
+		ball.Set("freeHit", nativeBoolToBooleanObject(d.FreeHit))
+		if d.Dismissal != "" {
+			ball.Set("dismissal", &object.String{Value: d.Dismissal})
+		}
+		ball.Set("score", &object.Integer{Value: d.Score})
+		ball.Set("wickets", &object.Integer{Value: d.Wickets})
+		balls[i] = ball
+	}
+	out.Set("balls", &object.Array{Elements: balls})
+	return out
+}
+
+func simMatchObject(result *sim.MatchResult) object.Object {
+	out := object.NewHash()
+	if result.Winner == "" {
+		out.Set("winner", DEAD_BALL)
+	} else {
+		out.Set("winner", &object.String{Value: result.Winner})
+	}
+	out.Set("margin", &object.String{Value: result.Margin})
+	out.Set("innings", &object.Array{Elements: []object.Object{
+		simInningsObject(result.Innings[0]),
+		simInningsObject(result.Innings[1]),
+	}})
+	return out
+}
@@ -0,0 +1,83 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"testing"
+)
+
+const simTeams = `
+player india = {"name": "India", "batters": ["Rohit", "Gill", "Kohli", "Surya", "Pant", "Hardik", "Jadeja", "Axar", "Kuldeep", "Bumrah", "Siraj"], "bowlers": ["Bumrah", "Siraj", "Hardik", "Kuldeep", "Jadeja"]};
+player sixes = {"name": "Sixes", "batters": [{"name": "Big", "outcomes": {"6": 1}}, {"name": "Hitter", "outcomes": [0, 0, 0, 0, 0, 1, 0, 0, 0]}], "bowlers": [{"name": "Loose", "outcomes": {"6": 1}}, {"name": "Looser", "outcomes": {"6": 1}}]};
+player strong = {"name": "Strong", "batters": [{"name": "Big", "outcomes": {"6": 1}}, {"name": "Hitter", "outcomes": {"6": 1}}], "bowlers": [{"name": "Quick", "outcomes": {"W": 1}}, {"name": "Quicker", "outcomes": {"W": 1}}]};
+`
+
+func TestSimulateInningsBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`simulateInnings(sixes, sixes, 2)["runs"]`, 72},
+		{`simulateInnings(sixes, sixes, 2)["overs"]`, "2.0"},
+		{`simulateInnings(sixes, sixes, 20, 30, 10)["runs"]`, 30},
+		{`simulateInnings(sixes, sixes, 20, 30, 10)["target"]`, 30},
+		{`len(simulateInnings(sixes, sixes, 2)["balls"])`, 12},
+		{`simulateInnings(sixes, sixes, 2)["balls"][6]["ball"]`, "1.1"},
+		{`simulateInnings(sixes, sixes, 2)["balls"][6]["bowler"]`, "Looser"},
+		{`simulateInnings(sixes, sixes, 2)["bowling"][0]["runs"]`, 36},
+		{`simulateInnings(sixes, sixes, 3)`, "simulateInnings: 2 bowlers of Sixes with 1 overs each can't bowl 3 overs"},
+		{`simulateInnings(sixes, sixes, 3, 0, 2)["overs"]`, "3.0"},
+		{`simulateInnings(sixes, {"name": "X", "batters": [], "bowlers": [1]}, 2)`, "simulateInnings: X bowlers must be STRING or HASH, got INTEGER"},
+		{`simulateInnings(sixes, {"name": "X", "batters": [], "bowlers": [{"name": "Y", "outcomes": {"7": 1}}]}, 2)`, `simulateInnings: unknown outcome "7" for Y`},
+		{`simulateInnings(sixes, {"batters": []}, 2)`, "simulateInnings: team name must be STRING"},
+		{`simulateInnings(sixes, 1, 2)`, "team for `simulateInnings` must be HASH, got INTEGER"},
+		{`simulateInnings(sixes, sixes)`, "wrong number of arguments. got=2, want=3 to 5"},
+	}
+
+	for _, tt := range tests {
+		input := simTeams + tt.input
+		testBuiltinResult(t, input, testEval(input), tt.expected)
+	}
+}
+
+func TestSimulateInningsScorecard(t *testing.T) {
+	input := simTeams + `simulateInnings(sixes, sixes, 1)["scorecard"]`
+	inn, ok := testEval(input).(*object.Innings)
+	if !ok {
+		t.Fatalf("scorecard is not Innings. got=%T", testEval(input))
+	}
+	if inn.Total != 36 || inn.Batters[0].Sixes+inn.Batters[1].Sixes != 6 {
+		t.Errorf("wrong scorecard:\n%s", inn.Inspect())
+	}
+}
+
+func TestSimulationIsSeeded(t *testing.T) {
+	input := simTeams + `
+	seed(11);
+	player first = simulateMatch({"teams": [india, sixes], "overs": 20, "quota": 10});
+	seed(11);
+	player second = simulateMatch({"teams": [india, sixes], "overs": 20, "quota": 10});
+	toJSON(first["innings"][0]["balls"]) == toJSON(second["innings"][0]["balls"])`
+
+	testBooleanObject(t, testEvalSeeded(input, 1), true)
+}
+
+func TestSimulateMatchBuiltin(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`simulateMatch({"teams": [india, strong], "overs": 2})["winner"]`, "Strong"},
+		{`len(simulateMatch({"teams": [india, sixes], "overs": 2})["innings"])`, 2},
+		{`simulateMatch({"teams": [india], "overs": 2})`, "simulateMatch: match teams must be an ARRAY of 2 teams"},
+		{`simulateMatch({"teams": [india, sixes]})`, "simulateMatch: match is missing overs"},
+		{`simulateMatch({"teams": [india, india], "overs": 2})`, "simulateMatch: both teams are called India"},
+		{`winProbability({"teams": [india, strong], "overs": 2}, 10)["wins"]["Strong"]`, 10},
+		{`winProbability({"teams": [india, sixes], "overs": 2}, 10)["simulations"]`, 10},
+		{`winProbability({"teams": [india, sixes], "overs": 2}, 0)`, "winProbability: simulations must be positive, got 0"},
+	}
+
+	for _, tt := range tests {
+		input := simTeams + tt.input
+		testBuiltinResult(t, input, testEvalSeeded(input, 5), tt.expected)
+	}
+}
@@ -0,0 +1,164 @@
+package evaluator
+
+import "CricLang/object"
+
+// The stats builtins take overs either as a string in overs notation,
+// "19.4", or as a count of balls, 118. Rates and averages with nothing to
+// divide by, such as a strike rate off no balls, are deadball.
+var statsBuiltins = map[string]*object.Builtin{
+	"toBalls": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := statArguments("toBalls", "o", args)
+			if misfield != nil {
+				return misfield
+			}
+			return &object.Integer{Value: int64(values[0])}
+		},
+	},
+	"toOvers": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := statArguments("toOvers", "o", args)
+			if misfield != nil {
+				return misfield
+			}
+			return &object.String{Value: object.FormatOvers(int64(values[0]))}
+		},
+	},
+	"strikeRate": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := statArguments("strikeRate", "no", args)
+			if misfield != nil {
+				return misfield
+			}
+			return ratio(values[0]*100, values[1])
+		},
+	},
+	"battingAverage": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := statArguments("battingAverage", "nnn", args)
+			if misfield != nil {
+				return misfield
+			}
+			runs, innings, notOuts := values[0], values[1], values[2]
+			if notOuts > innings {
+				return newMisfield("battingAverage: %v not outs in %v innings", notOuts, innings)
+			}
+			return ratio(runs, innings-notOuts)
+		},
+	},
+	"economy": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := statArguments("economy", "no", args)
+			if misfield != nil {
+				return misfield
+			}
+			return ratio(values[0]*object.BallsPerOver, values[1])
+		},
+	},
+	"bowlingAverage": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := statArguments("bowlingAverage", "nn", args)
+			if misfield != nil {
+				return misfield
+			}
+			return ratio(values[0], values[1])
+		},
+	},
+	"bowlingStrikeRate": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := statArguments("bowlingStrikeRate", "on", args)
+			if misfield != nil {
+				return misfield
+			}
+			return ratio(values[0], values[1])
+		},
+	},
+	"runRate": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := statArguments("runRate", "no", args)
+			if misfield != nil {
+				return misfield
+			}
+			return ratio(values[0]*object.BallsPerOver, values[1])
+		},
+	},
+	"requiredRunRate": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := statArguments("requiredRunRate", "nno", args)
+			if misfield != nil {
+				return misfield
+			}
+			target, runs, remaining := values[0], values[1], values[2]
+			return ratio((target-runs)*object.BallsPerOver, remaining)
+		},
+	},
+	// netRunRate takes the runs and overs a team scored and faced, then the
+	// runs and overs it conceded and bowled. A side bowled out counts its
+	// full quota of overs, which is for the caller to pass.
+	"netRunRate": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := statArguments("netRunRate", "nono", args)
+			if misfield != nil {
+				return misfield
+			}
+			if values[1] == 0 || values[3] == 0 {
+				return DEAD_BALL
+			}
+			scored := values[0] * object.BallsPerOver / values[1]
+			conceded := values[2] * object.BallsPerOver / values[3]
+			return &object.Float{Value: scored - conceded}
+		},
+	},
+}
+
+// statArguments checks args against kinds, one letter per argument: 'n'
+// for a number and 'o' for overs, which comes back as a ball count.
+func statArguments(name, kinds string, args []object.Object) ([]float64, *object.Misfield) {
+	if len(args) != len(kinds) {
+		return nil, newMisfield("wrong number of arguments. got=%d, want=%d", len(args), len(kinds))
+	}
+
+	values := make([]float64, len(args))
+	for i, arg := range args {
+		switch kinds[i] {
+		case 'n':
+			if !isNumber(arg) {
+				return nil, newMisfield("argument to `%s` must be INTEGER or FLOAT, got %s", name, arg.Type())
+			}
+			values[i] = toFloat(arg)
+		case 'o':
+			balls, misfield := oversArgument(name, arg)
+			if misfield != nil {
+				return nil, misfield
+			}
+			values[i] = float64(balls)
+		}
+	}
+	return values, nil
+}
+
+// oversArgument reads overs given as "19.4" or as a ball count.
+func oversArgument(name string, arg object.Object) (int64, *object.Misfield) {
+	switch arg := arg.(type) {
+	case *object.Integer:
+		if arg.Value < 0 {
+			return 0, newMisfield("%s: balls can't be negative, got %d", name, arg.Value)
+		}
+		return arg.Value, nil
+	case *object.String:
+		balls, err := object.ParseOvers(arg.Value)
+		if err != nil {
+			return 0, newMisfield("%s: %s", name, err)
+		}
+		return balls, nil
+	default:
+		return 0, newMisfield("overs for `%s` must be STRING like \"19.4\" or a ball count, got %s", name, arg.Type())
+	}
+}
+
+func ratio(numerator, denominator float64) object.Object {
+	if denominator == 0 {
+		return DEAD_BALL
+	}
+	return &object.Float{Value: numerator / denominator}
+}
@@ -0,0 +1,66 @@
+package evaluator
+
+import "testing"
+
+func TestOversConversion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`toBalls("19.4")`, 118},
+		{`toBalls("20")`, 120},
+		{`toBalls("20.0")`, 120},
+		{`toBalls("0.5")`, 5},
+		{`toBalls(118)`, 118},
+		{`toOvers(118)`, "19.4"},
+		{`toOvers(120)`, "20.0"},
+		{`toOvers("19.4")`, "19.4"},
+		{`toOvers(0)`, "0.0"},
+		{`toBalls("19.6")`, `toBalls: invalid overs "19.6": the part after the point counts balls, 0 to 5`},
+		{`toBalls("19.45")`, `toBalls: invalid overs "19.45": the part after the point counts balls, 0 to 5`},
+		{`toBalls("-1")`, `toBalls: invalid overs "-1"`},
+		{`toBalls("nineteen")`, `toBalls: invalid overs "nineteen"`},
+		{`toBalls(-6)`, "toBalls: balls can't be negative, got -6"},
+		{`toBalls(float(19))`, "overs for `toBalls` must be STRING like \"19.4\" or a ball count, got FLOAT"},
+		{`toOvers()`, "wrong number of arguments. got=0, want=1"},
+	}
+
+	for _, tt := range tests {
+		testBuiltinResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestStatsBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`strikeRate(82, 53)`, 8200.0 / 53},
+		{`strikeRate(0, 0)`, nil},
+		{`battingAverage(500, 12, 2)`, 50.0},
+		{`battingAverage(120, 3, 3)`, nil},
+		{`battingAverage(120, 3, 4)`, "battingAverage: 4 not outs in 3 innings"},
+		{`economy(30, "4.0")`, 7.5},
+		{`economy(30, 24)`, 7.5},
+		{`economy(25, "3.2")`, 7.5},
+		{`bowlingAverage(300, 12)`, 25.0},
+		{`bowlingAverage(40, 0)`, nil},
+		{`bowlingStrikeRate("20.0", 4)`, 30.0},
+		{`bowlingStrikeRate(120, 0)`, nil},
+		{`runRate(177, "19.4")`, 9.0},
+		{`runRate(177, 118)`, 9.0},
+		{`runRate(10, "0.0")`, nil},
+		{`requiredRunRate(180, 120, "10.0")`, 6.0},
+		{`requiredRunRate(180, 150, 30)`, 6.0},
+		{`netRunRate(180, "20.0", 150, "20.0")`, 1.5},
+		{`netRunRate(177, "19.4", 176, 120)`, 9.0 - 8.8},
+		{`netRunRate(177, 0, 176, 120)`, nil},
+		{`strikeRate("82", 53)`, "argument to `strikeRate` must be INTEGER or FLOAT, got STRING"},
+		{`economy(30, "4.7")`, `economy: invalid overs "4.7": the part after the point counts balls, 0 to 5`},
+		{`runRate(177)`, "wrong number of arguments. got=1, want=2"},
+	}
+
+	for _, tt := range tests {
+		testMathResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
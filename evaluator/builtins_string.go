@@ -0,0 +1,190 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxStringLength caps the strings builtins like repeat can build, in
+// bytes, so a large count misfields instead of exhausting memory.
+const maxStringLength = 1 << 26
+
+var stringBuiltins = map[string]*object.Builtin{
+	"split": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := stringArguments("split", 2, args)
+			if misfield != nil {
+				return misfield
+			}
+			parts := strings.Split(values[0], values[1])
+			elements := make([]object.Object, len(parts))
+			for i, part := range parts {
+				elements[i] = &object.String{Value: part}
+			}
+			return &object.Array{Elements: elements}
+		},
+	},
+	"join": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newMisfield("wrong number of arguments. got=%d, want=2", len(args))
+			}
+			arr, ok := args[0].(*object.Array)
+			if !ok {
+				return newMisfield("argument to `join` must be ARRAY, got %s", args[0].Type())
+			}
+			sep, ok := args[1].(*object.String)
+			if !ok {
+				return newMisfield("separator for `join` must be STRING, got %s", args[1].Type())
+			}
+			parts := make([]string, len(arr.Elements))
+			for i, e := range arr.Elements {
+				parts[i] = e.Inspect()
+			}
+			return &object.String{Value: strings.Join(parts, sep.Value)}
+		},
+	},
+	"contains": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := stringArguments("contains", 2, args)
+			if misfield != nil {
+				return misfield
+			}
+			return nativeBoolToBooleanObject(strings.Contains(values[0], values[1]))
+		},
+	},
+	"startsWith": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := stringArguments("startsWith", 2, args)
+			if misfield != nil {
+				return misfield
+			}
+			return nativeBoolToBooleanObject(strings.HasPrefix(values[0], values[1]))
+		},
+	},
+	"endsWith": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := stringArguments("endsWith", 2, args)
+			if misfield != nil {
+				return misfield
+			}
+			return nativeBoolToBooleanObject(strings.HasSuffix(values[0], values[1]))
+		},
+	},
+	"upper": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := stringArguments("upper", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			return &object.String{Value: strings.ToUpper(values[0])}
+		},
+	},
+	"lower": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := stringArguments("lower", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			return &object.String{Value: strings.ToLower(values[0])}
+		},
+	},
+	"trim": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) == 2 {
+				values, misfield := stringArguments("trim", 2, args)
+				if misfield != nil {
+					return misfield
+				}
+				return &object.String{Value: strings.Trim(values[0], values[1])}
+			}
+			values, misfield := stringArguments("trim", 1, args)
+			if misfield != nil {
+				return misfield
+			}
+			return &object.String{Value: strings.TrimSpace(values[0])}
+		},
+	},
+	"replace": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			values, misfield := stringArguments("replace", 3, args)
+			if misfield != nil {
+				return misfield
+			}
+			return &object.String{Value: strings.ReplaceAll(values[0], values[1], values[2])}
+		},
+	},
+	"repeat": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newMisfield("wrong number of arguments. got=%d, want=2", len(args))
+			}
+			str, ok := args[0].(*object.String)
+			if !ok {
+				return newMisfield("argument to `repeat` must be STRING, got %s", args[0].Type())
+			}
+			count, ok := args[1].(*object.Integer)
+			if !ok {
+				return newMisfield("count for `repeat` must be INTEGER, got %s", args[1].Type())
+			}
+			if count.Value < 0 {
+				return newMisfield("count for `repeat` must not be negative, got %d", count.Value)
+			}
+			// divide rather than multiply so a huge count can't overflow
+			if len(str.Value) > 0 && count.Value > int64(maxStringLength/len(str.Value)) {
+				return newMisfield("repeat: result would be longer than %d bytes", maxStringLength)
+			}
+			return &object.String{Value: strings.Repeat(str.Value, int(count.Value))}
+		},
+	},
+	"substring": &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			if len(args) != 2 && len(args) != 3 {
+				return newMisfield("wrong number of arguments. got=%d, want=2 or 3", len(args))
+			}
+			str, ok := args[0].(*object.String)
+			if !ok {
+				return newMisfield("argument to `substring` must be STRING, got %s", args[0].Type())
+			}
+			runes := []rune(str.Value)
+			length := int64(len(runes))
+
+			bounds := []int64{0, length}
+			for i, arg := range args[1:] {
+				integer, ok := arg.(*object.Integer)
+				if !ok {
+					return newMisfield("bounds of `substring` must be INTEGER, got %s", arg.Type())
+				}
+				bounds[i] = clampIndex(integer.Value, length)
+			}
+
+			start, end := bounds[0], bounds[1]
+			if start > end {
+				start = end
+			}
+			return &object.String{Value: string(runes[start:end])}
+		},
+	},
+}
+
+// stringArguments checks a builtin got want arguments, all strings, and
+// returns their values.
+func stringArguments(name string, want int, args []object.Object) ([]string, *object.Misfield) {
+	if len(args) != want {
+		return nil, newMisfield("wrong number of arguments. got=%d, want=%d", len(args), want)
+	}
+	values := make([]string, len(args))
+	for i, arg := range args {
+		str, ok := arg.(*object.String)
+		if !ok {
+			return nil, newMisfield("argument to `%s` must be STRING, got %s", name, arg.Type())
+		}
+		values[i] = str.Value
+	}
+	return values, nil
+}
+
+func stringLength(s string) int64 {
+	return int64(utf8.RuneCountInString(s))
+}
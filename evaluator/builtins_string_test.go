@@ -0,0 +1,59 @@
+package evaluator
+
+import "testing"
+
+func TestStringBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`len("")`, 0},
+		{`len("four")`, 4},
+		{`len("héllo")`, 5},
+		{`len("🏏🏏")`, 2},
+		{`join(split("4,6,W", ","), "|")`, "4|6|W"},
+		{`len(split("4 6 W", " "))`, 3},
+		{`split("abc", "")[1]`, "b"},
+		{`split(1, ",")`, "argument to `split` must be STRING, got INTEGER"},
+		{`join([1, "two", 3], "-")`, "1-two-3"},
+		{`join([], "-")`, ""},
+		{`join("a", "-")`, "argument to `join` must be ARRAY, got STRING"},
+		{`join(["a"], 1)`, "separator for `join` must be STRING, got INTEGER"},
+		{`contains("caught behind", "behind")`, true},
+		{`contains("caught behind", "bowled")`, false},
+		{`startsWith("lbw appeal", "lbw")`, true},
+		{`startsWith("lbw appeal", "appeal")`, false},
+		{`endsWith("lbw appeal", "appeal")`, true},
+		{`upper("Dhoni")`, "DHONI"},
+		{`lower("Dhoni")`, "dhoni"},
+		{`upper("Dhoni", "x")`, "wrong number of arguments. got=2, want=1"},
+		{`trim("  six!  ")`, "six!"},
+		{`trim("--six--", "-")`, "six"},
+		{`trim(5)`, "argument to `trim` must be STRING, got INTEGER"},
+		{`replace("four four", "four", "six")`, "six six"},
+		{`replace("four", "four")`, "wrong number of arguments. got=2, want=3"},
+		{`repeat("ab", 3)`, "ababab"},
+		{`repeat("ab", 0)`, ""},
+		{`repeat("ab", -1)`, "count for `repeat` must not be negative, got -1"},
+		{`repeat("ab", "3")`, "count for `repeat` must be INTEGER, got STRING"},
+		{`repeat("a", 100000000000)`, "repeat: result would be longer than 67108864 bytes"},
+		{`repeat("ab", 4611686018427387904)`, "repeat: result would be longer than 67108864 bytes"},
+		{`repeat("", 100000000000)`, ""},
+		{`len(repeat("ab", 33554432))`, 67108864},
+		{`substring("sachin tendulkar", 7)`, "tendulkar"},
+		{`substring("sachin tendulkar", 0, 6)`, "sachin"},
+		{`substring("héllo", 1, 3)`, "él"},
+		{`substring("sachin", -3)`, "hin"},
+		{`substring("sachin", 4, 2)`, ""},
+		{`substring("sachin", "1")`, "bounds of `substring` must be INTEGER, got STRING"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if expected, ok := tt.expected.(bool); ok {
+			testBooleanObject(t, evaluated, expected)
+			continue
+		}
+		testBuiltinResult(t, tt.input, evaluated, tt.expected)
+	}
+}
@@ -53,6 +53,9 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isMisfield(val) {
 			return val
 		}
+		if node.Pattern != nil {
+			return destructure(node.Pattern, val, env, val.Inspect())
+		}
 		env.Set(node.Name.Value, val)
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
@@ -60,7 +63,19 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		params := node.Parameters
 		body := node.Body
 		return &object.Field{Parameters: params, Env: env, Body: body}
+	case *ast.MacroLiteral:
+		return &object.Macro{Parameters: node.Parameters, Env: env, Body: node.Body}
 	case *ast.CallExpression:
+		if node.Function.TokenLiteral() == "quote" {
+			if len(node.Arguments) != 1 {
+				return newMisfield("wrong number of arguments to quote. got=%d, want=1", len(node.Arguments))
+			}
+			return quote(node.Arguments[0], env)
+		}
+		if node.Function.TokenLiteral() == "import" {
+			return evalImport(node, env)
+		}
+
 		function := Eval(node.Function, env)
 		if isMisfield(function) {
 			return function
@@ -69,7 +84,7 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if len(args) == 1 && isMisfield(args[0]) {
 			return args[0]
 		}
-		return applyField(function, args)
+		return applyField(function, args, env)
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
 	case *ast.ArrayLiteral:
@@ -78,6 +93,8 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return elements[0]
 		}
 		return &object.Array{Elements: elements}
+	case *ast.HashLiteral:
+		return evalHashLiteral(node, env)
 	case *ast.IndexExpression:
 		left := Eval(node.Left, env)
 		if isMisfield(left) {
@@ -90,6 +107,20 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		}
 
 		return evalIndexExpression(left, index)
+	case *ast.DotExpression:
+		left := Eval(node.Left, env)
+		if isMisfield(left) {
+			return left
+		}
+		return evalDotExpression(left, node.Name.Value)
+	case *ast.AssignExpression:
+		return evalAssignExpression(node, env)
+	case *ast.SquadStatement:
+		return evalSquadStatement(node, env)
+	case *ast.UmpireExpression:
+		return evalUmpireExpression(node, env)
+	case *ast.RestElement:
+		return newMisfield("%s can only be used in a pattern", node.String())
 	}
 	return nil
 }
@@ -158,18 +189,22 @@ func evalBangOperatorExpression(right object.Object) object.Object {
 }
 
 func evalMinusOperatorExpression(right object.Object) object.Object {
-	if right.Type() != object.INTEGER_OBJ {
+	switch right := right.(type) {
+	case *object.Integer:
+		return &object.Integer{Value: -right.Value}
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+	default:
 		return newMisfield("unknown operator team: -%s", right.Type())
 	}
-
-	value := right.(*object.Integer).Value
-	return &object.Integer{Value: -value}
 }
 
 func evalInfixExpression(operator string, left, right object.Object) object.Object {
 	switch {
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		return evalIntegerInfixExpression(operator, left, right)
+	case isNumber(left) && isNumber(right):
+		return evalFloatInfixExpression(operator, left, right)
 	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
 		return evalStringInfixExpression(operator, left, right)
 	case left.Type() != right.Type():
@@ -209,6 +244,47 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 	}
 }
 
+// evalFloatInfixExpression handles any arithmetic involving a float; an
+// integer on the other side is promoted.
+func evalFloatInfixExpression(operator string, left, right object.Object) object.Object {
+	leftVal := toFloat(left)
+	rightVal := toFloat(right)
+
+	switch operator {
+	case "+":
+		return &object.Float{Value: leftVal + rightVal}
+	case "-":
+		return &object.Float{Value: leftVal - rightVal}
+	case "*":
+		return &object.Float{Value: leftVal * rightVal}
+	case "/":
+		return &object.Float{Value: leftVal / rightVal}
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
+		return newMisfield("unknown operator team: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func isNumber(obj object.Object) bool {
+	return obj.Type() == object.INTEGER_OBJ || obj.Type() == object.FLOAT_OBJ
+}
+
+// toFloat converts an integer or float to a float64. It must only be
+// called on objects isNumber accepts.
+func toFloat(obj object.Object) float64 {
+	if integer, ok := obj.(*object.Integer); ok {
+		return float64(integer.Value)
+	}
+	return obj.(*object.Float).Value
+}
+
 func evalAppealIfExpression(ie *ast.AppealIfExpression, env *object.Environment) object.Object {
 	condition := Eval(ie.Condition, env)
 	if isMisfield(condition) {
@@ -256,6 +332,10 @@ func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object
 	if builtin, ok := builtins[node.Value]; ok {
 		return builtin
 	}
+
+	if constant, ok := constants[node.Value]; ok {
+		return constant
+	}
 	return newMisfield("identifier not found: " + node.Value)
 }
 
@@ -273,26 +353,50 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 	return result
 }
 
-func applyField(fn object.Object, args []object.Object) object.Object {
+func applyField(fn object.Object, args []object.Object, env *object.Environment) object.Object {
 	switch fn := fn.(type) {
 	case *object.Field:
-		extendedEnv := extendFunctionEnv(fn, args)
+		if len(args) != len(fn.Parameters) {
+			return newMisfield("wrong number of arguments to field. got=%d, want=%d", len(args), len(fn.Parameters))
+		}
+		extendedEnv, misfield := extendFunctionEnv(fn, args)
+		if misfield != nil {
+			return misfield
+		}
 		evaluated := Eval(fn.Body, extendedEnv)
 		return unwrapSignalDecisionValue(evaluated)
 	case *object.Builtin:
-		return fn.Fn(args...)
+		return fn.Fn(newCallContext(env), args...)
+	case *object.Squad:
+		return newInstance(fn, args)
 	default:
 		return newMisfield("not a field: %s", fn.Type())
 	}
 }
 
-func extendFunctionEnv(fn *object.Field, args []object.Object) *object.Environment {
+func newCallContext(env *object.Environment) *object.CallContext {
+	return &object.CallContext{
+		Env: env,
+		Apply: func(fn object.Object, args ...object.Object) object.Object {
+			return applyField(fn, args, env)
+		},
+	}
+}
+
+func extendFunctionEnv(fn *object.Field, args []object.Object) (*object.Environment, object.Object) {
 	env := object.NewEnclosedEnvironment(fn.Env)
 
 	for paramIdx, param := range fn.Parameters {
-		env.Set(param.Value, args[paramIdx])
+		if ident, ok := param.(*ast.Identifier); ok {
+			env.Set(ident.Value, args[paramIdx])
+			continue
+		}
+		what := fmt.Sprintf("argument %d to field", paramIdx+1)
+		if misfield := destructure(param, args[paramIdx], env, what); misfield != nil {
+			return nil, misfield
+		}
 	}
-	return env
+	return env, nil
 }
 
 func unwrapSignalDecisionValue(obj object.Object) object.Object {
@@ -303,19 +407,37 @@ func unwrapSignalDecisionValue(obj object.Object) object.Object {
 }
 
 func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
-	if operator != "+" {
-		return newMisfield("unknown operator team: %s %s %s", left.Type(), operator, right.Type())
-	}
-
 	leftVal := left.(*object.String).Value
 	rightVal := right.(*object.String).Value
-	return &object.String{Value: leftVal + rightVal}
+
+	switch operator {
+	case "+":
+		return &object.String{Value: leftVal + rightVal}
+	case "<":
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case ">":
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "==":
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
+		return newMisfield("unknown operator team: %s %s %s", left.Type(), operator, right.Type())
+	}
 }
 
 func evalIndexExpression(left, index object.Object) object.Object {
 	switch {
 	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
 		return evalArrayIndexExpression(left, index)
+	case left.Type() == object.STRING_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalStringIndexExpression(left, index)
+	case left.Type() == object.HASH_OBJ:
+		return evalHashIndexExpression(left, index)
+	case left.Type() == object.MODULE_OBJ && index.Type() == object.STRING_OBJ:
+		return evalModuleIndexExpression(left, index)
+	case left.Type() == object.INNINGS_OBJ && index.Type() == object.STRING_OBJ:
+		return evalInningsIndexExpression(left, index)
 	default:
 		return newMisfield("index operator team not allowed: %s", left.Type())
 	}
@@ -332,3 +454,51 @@ func evalArrayIndexExpression(array, index object.Object) object.Object {
 
 	return arrayObject.Elements[idx]
 }
+
+// evalStringIndexExpression indexes by rune, so "héllo"[1] is "é".
+func evalStringIndexExpression(str, index object.Object) object.Object {
+	runes := []rune(str.(*object.String).Value)
+	idx := index.(*object.Integer).Value
+
+	if idx < 0 || idx >= int64(len(runes)) {
+		return DEAD_BALL
+	}
+
+	return &object.String{Value: string(runes[idx])}
+}
+
+func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
+	hash := object.NewHash()
+
+	for _, pair := range node.Pairs {
+		key := Eval(pair.Key, env)
+		if isMisfield(key) {
+			return key
+		}
+		str, ok := key.(*object.String)
+		if !ok {
+			return newMisfield("unusable as hash key: %s", key.Type())
+		}
+
+		value := Eval(pair.Value, env)
+		if isMisfield(value) {
+			return value
+		}
+
+		hash.Set(str.Value, value)
+	}
+
+	return hash
+}
+
+func evalHashIndexExpression(hash, index object.Object) object.Object {
+	key, ok := index.(*object.String)
+	if !ok {
+		return newMisfield("unusable as hash key: %s", index.Type())
+	}
+
+	if value, ok := hash.(*object.Hash).Get(key.Value); ok {
+		return value
+	}
+	return DEAD_BALL
+}
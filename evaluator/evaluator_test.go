@@ -1,6 +1,7 @@
 package evaluator
 
 import (
+	"CricLang/ast"
 	"CricLang/lexer"
 	"CricLang/object"
 	"CricLang/parser"
@@ -387,3 +388,260 @@ func TestArrayIndexExpressions(t *testing.T) {
 		}
 	}
 }
+
+func TestJSONDecodedProgramEvaluatesTheSame(t *testing.T) {
+	inputs := []string{
+		"(5 + 10 * 2 + 15 / 3) * 2 + -10",
+		"player add = field(x, y) {x + y;}; add(5+5, add(5, 5));",
+		"appeal (1 > 2) { 10 } appealrejected { 20 }",
+		`player newAdder = field(x){ field(y){x + y;} }; newAdder(2)(2);`,
+		`player a = ["Hello", " ", "World!"]; a[0] + a[1] + a[2]`,
+		"5 + notout;",
+	}
+
+	for _, input := range inputs {
+		program := parser.New(lexer.New(input)).ParseProgram()
+
+		data, err := ast.MarshalJSON(program)
+		if err != nil {
+			t.Fatalf("MarshalJSON failed for %q: %s", input, err)
+		}
+		decoded, err := ast.UnmarshalProgramJSON(data)
+		if err != nil {
+			t.Fatalf("UnmarshalProgramJSON failed for %q: %s", input, err)
+		}
+
+		want := Eval(program, object.NewEnvironment())
+		got := Eval(decoded, object.NewEnvironment())
+		if want.Type() != got.Type() || want.Inspect() != got.Inspect() {
+			t.Errorf("decoded program evaluated differently for %q. want=%s, got=%s",
+				input, want.Inspect(), got.Inspect())
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`quote(5)`, `5`},
+		{`quote(5 + 8)`, `(5 + 8)`},
+		{`quote(foobar)`, `foobar`},
+		{`quote(foobar + barfoo)`, `(foobar + barfoo)`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		quote, ok := evaluated.(*object.Quote)
+		if !ok {
+			t.Fatalf("expected *object.Quote. got=%T (%+v)", evaluated, evaluated)
+		}
+
+		if quote.Node == nil {
+			t.Fatalf("quote.Node is nil")
+		}
+
+		if quote.Node.String() != tt.expected {
+			t.Errorf("not equal. got=%q, want=%q", quote.Node.String(), tt.expected)
+		}
+	}
+}
+
+func TestQuoteUnquote(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`quote(unquote(4))`, `4`},
+		{`quote(unquote(4 + 4))`, `8`},
+		{`quote(8 + unquote(4 + 4))`, `(8 + 8)`},
+		{`quote(unquote(4 + 4) + 8)`, `(8 + 8)`},
+		{`quote(unquote(0 - 4))`, `(-4)`},
+		{`player foobar = 8; quote(foobar)`, `foobar`},
+		{`player foobar = 8; quote(unquote(foobar))`, `8`},
+		{`quote(unquote(notout))`, `notout`},
+		{`quote(unquote(notout == out))`, `out`},
+		{`quote(unquote("wicket"))`, `wicket`},
+		{`quote(unquote([1, "two", out]))`, `[1, two, out]`},
+		{`quote(unquote({"runs": 4}))`, `{runs: 4}`},
+		{`quote(unquote(quote(4 + 4)))`, `(4 + 4)`},
+		{`player quotedInfixExpression = quote(4 + 4);
+		quote(unquote(4 + 4) + unquote(quotedInfixExpression))`, `(8 + (4 + 4))`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		quote, ok := evaluated.(*object.Quote)
+		if !ok {
+			t.Fatalf("expected *object.Quote. got=%T (%+v)", evaluated, evaluated)
+		}
+
+		if quote.Node == nil {
+			t.Fatalf("quote.Node is nil")
+		}
+
+		if quote.Node.String() != tt.expected {
+			t.Errorf("not equal. got=%q, want=%q", quote.Node.String(), tt.expected)
+		}
+	}
+}
+
+func TestQuoteDoesNotRewriteProgram(t *testing.T) {
+	input := `
+	player q = field(x) { quote(unquote(x) + 1) };
+	q(1);
+	q(2);
+	`
+
+	evaluated := testEval(input)
+	quote, ok := evaluated.(*object.Quote)
+	if !ok {
+		t.Fatalf("expected *object.Quote. got=%T (%+v)", evaluated, evaluated)
+	}
+	if quote.Node.String() != "(2 + 1)" {
+		t.Errorf("not equal. got=%q, want=%q", quote.Node.String(), "(2 + 1)")
+	}
+}
+
+func TestQuoteMisfields(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`quote(1, 2)`, "wrong number of arguments to quote. got=2, want=1"},
+		{`quote(unquote(foobar))`, "identifier not found: foobar"},
+		{`quote(unquote(field(x) { x }))`, "cannot unquote FIELD into the quoted program"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		errObj, ok := evaluated.(*object.Misfield)
+		if !ok {
+			t.Errorf("no error object returned for %q. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong error message. expected=%q, got=%q", tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestStringComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{`"dhoni" == "dhoni"`, true},
+		{`"dhoni" == "kohli"`, false},
+		{`"dhoni" != "kohli"`, true},
+		{`"dhoni" != "dhoni"`, false},
+		{`"dhoni" < "kohli"`, true},
+		{`"kohli" < "dhoni"`, false},
+		{`"kohli" > "dhoni"`, true},
+		{`"a" + "b" == "ab"`, true},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testBooleanObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestStringIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"dhoni"[0]`, "d"},
+		{`"dhoni"[4]`, "i"},
+		{`player s = "héllo"; s[1]`, "é"},
+		{`"🏏 six"[0]`, "🏏"},
+		{`"dhoni"[5]`, nil},
+		{`"dhoni"[-1]`, nil},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		expected, ok := tt.expected.(string)
+		if !ok {
+			testNullObject(t, evaluated)
+			continue
+		}
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Errorf("object is not String. got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+		if str.Value != expected {
+			t.Errorf("String has wrong value. want=%q, got=%q", expected, str.Value)
+		}
+	}
+}
+
+func TestHashLiterals(t *testing.T) {
+	input := `player two = "two";
+	{
+		"one": 10 - 9,
+		two: 1 + 1,
+		"thr" + "ee": 6 / 2,
+		"one": 4
+	}`
+
+	evaluated := testEval(input)
+	result, ok := evaluated.(*object.Hash)
+	if !ok {
+		t.Fatalf("Eval didn't return Hash. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	expectedKeys := []string{"one", "two", "three"}
+	expected := map[string]int64{"one": 4, "two": 2, "three": 3}
+
+	if len(result.Keys) != len(expectedKeys) {
+		t.Fatalf("Hash has wrong num of keys. got=%d", len(result.Keys))
+	}
+
+	for i, key := range expectedKeys {
+		if result.Keys[i] != key {
+			t.Errorf("key %d wrong. want=%q, got=%q", i, key, result.Keys[i])
+		}
+		testIntegerObject(t, result.Pairs[key], expected[key])
+	}
+
+	if result.Inspect() != "{one: 4, two: 2, three: 3}" {
+		t.Errorf("wrong Inspect. got=%q", result.Inspect())
+	}
+}
+
+func TestHashIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`{"foo": 5}["foo"]`, 5},
+		{`{"foo": 5}["bar"]`, nil},
+		{`player key = "foo"; {"foo": 5}[key]`, 5},
+		{`{}["foo"]`, nil},
+		{`{"a": {"b": 7}}["a"]["b"]`, 7},
+		{`{"foo": 5}[1]`, "unusable as hash key: INTEGER"},
+		{`{1: 5}`, "unusable as hash key: INTEGER"},
+		{`{"a": 1 + notout}`, "player type mismatch: INTEGER + BOOLEAN"},
+		{`len({"a": 1, "b": 2})`, 2},
+		{`join(keys({"b": 1, "a": 2}), ",")`, "b,a"},
+		{`values({"b": 1, "a": 2})`, []int{1, 2}},
+		{`has({"a": 1}, "a")`, true},
+		{`has({"a": 1}, "b")`, false},
+		{`has({"a": 1}, 1)`, "unusable as hash key: INTEGER"},
+		{`keys([1])`, "argument to `keys` must be HASH, got ARRAY"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if expected, ok := tt.expected.(bool); ok {
+			testBooleanObject(t, evaluated, expected)
+			continue
+		}
+		testBuiltinResult(t, tt.input, evaluated, tt.expected)
+	}
+}
@@ -0,0 +1,143 @@
+package evaluator
+
+import (
+	"CricLang/ast"
+	"CricLang/object"
+)
+
+// DefineMacros moves every top-level `player name = macro(...) {...}`
+// out of program and into env, so ExpandMacros can find them.
+func DefineMacros(program *ast.Program, env *object.Environment) {
+	definitions := []int{}
+
+	for i, statement := range program.Statements {
+		if isMacroDefinition(statement) {
+			addMacro(statement, env)
+			definitions = append(definitions, i)
+		}
+	}
+
+	for i := len(definitions) - 1; i >= 0; i-- {
+		definitionIndex := definitions[i]
+		program.Statements = append(
+			program.Statements[:definitionIndex],
+			program.Statements[definitionIndex+1:]...,
+		)
+	}
+}
+
+func isMacroDefinition(node ast.Statement) bool {
+	playerStatement, ok := node.(*ast.PlayerStatement)
+	if !ok || playerStatement.Name == nil {
+		return false
+	}
+
+	_, ok = playerStatement.Value.(*ast.MacroLiteral)
+	return ok
+}
+
+func addMacro(stmt ast.Statement, env *object.Environment) {
+	playerStatement, _ := stmt.(*ast.PlayerStatement)
+	macroLiteral, _ := playerStatement.Value.(*ast.MacroLiteral)
+
+	macro := &object.Macro{
+		Parameters: macroLiteral.Parameters,
+		Env:        env,
+		Body:       macroLiteral.Body,
+	}
+
+	env.Set(playerStatement.Name.Value, macro)
+}
+
+// ExpandMacros replaces every call to a macro defined in env with the
+// program the macro returns. Macros receive their arguments unevaluated,
+// as quotes. The first macro that misfields, or returns something that
+// can't be turned back into source, stops the expansion.
+func ExpandMacros(program ast.Node, env *object.Environment) (ast.Node, *object.Misfield) {
+	var misfield *object.Misfield
+
+	expanded := ast.Modify(program, func(node ast.Node) ast.Node {
+		if misfield != nil {
+			return node
+		}
+
+		callExpression, ok := node.(*ast.CallExpression)
+		if !ok {
+			return node
+		}
+
+		macro, ok := isMacroCall(callExpression, env)
+		if !ok {
+			return node
+		}
+
+		if len(callExpression.Arguments) != len(macro.Parameters) {
+			misfield = newMisfield("wrong number of arguments to macro %s. got=%d, want=%d",
+				callExpression.Function.String(), len(callExpression.Arguments), len(macro.Parameters))
+			return node
+		}
+
+		args := quoteArgs(callExpression)
+		evalEnv := extendMacroEnv(macro, args)
+
+		evaluated := unwrapSignalDecisionValue(Eval(macro.Body, evalEnv))
+		if m, ok := evaluated.(*object.Misfield); ok {
+			misfield = m
+			return node
+		}
+
+		if evaluated == nil {
+			misfield = newMisfield("macro %s returned nothing", callExpression.Function.String())
+			return node
+		}
+
+		expansion, ok := convertObjectToASTNode(evaluated)
+		if !ok {
+			misfield = newMisfield("macro %s must return a quote, got %s",
+				callExpression.Function.String(), evaluated.Type())
+			return node
+		}
+		return expansion
+	})
+
+	return expanded, misfield
+}
+
+func isMacroCall(exp *ast.CallExpression, env *object.Environment) (*object.Macro, bool) {
+	identifier, ok := exp.Function.(*ast.Identifier)
+	if !ok {
+		return nil, false
+	}
+
+	obj, ok := env.Get(identifier.Value)
+	if !ok {
+		return nil, false
+	}
+
+	macro, ok := obj.(*object.Macro)
+	if !ok {
+		return nil, false
+	}
+
+	return macro, true
+}
+
+func quoteArgs(exp *ast.CallExpression) []*object.Quote {
+	args := []*object.Quote{}
+
+	for _, a := range exp.Arguments {
+		args = append(args, &object.Quote{Node: a})
+	}
+
+	return args
+}
+
+func extendMacroEnv(macro *object.Macro, args []*object.Quote) *object.Environment {
+	extended := object.NewEnclosedEnvironment(macro.Env)
+
+	for paramIdx, param := range macro.Parameters {
+		extended.Set(param.Value, args[paramIdx])
+	}
+
+	return extended
+}
@@ -0,0 +1,165 @@
+package evaluator
+
+import (
+	"CricLang/ast"
+	"CricLang/lexer"
+	"CricLang/object"
+	"CricLang/parser"
+	"testing"
+)
+
+func TestDefineMacros(t *testing.T) {
+	input := `
+	player number = 1;
+	player function = field(x, y) { x + y };
+	player mymacro = macro(x, y) { x + y; };
+	`
+
+	env := object.NewEnvironment()
+	program := testParseProgram(input)
+
+	DefineMacros(program, env)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("Wrong number of statements. got=%d", len(program.Statements))
+	}
+
+	_, ok := env.Get("number")
+	if ok {
+		t.Fatalf("number should not be defined")
+	}
+	_, ok = env.Get("function")
+	if ok {
+		t.Fatalf("function should not be defined")
+	}
+
+	obj, ok := env.Get("mymacro")
+	if !ok {
+		t.Fatalf("macro not in environment.")
+	}
+
+	macro, ok := obj.(*object.Macro)
+	if !ok {
+		t.Fatalf("object is not Macro. got=%T (%+v)", obj, obj)
+	}
+
+	if len(macro.Parameters) != 2 {
+		t.Fatalf("Wrong number of macro parameters. got=%d", len(macro.Parameters))
+	}
+
+	if macro.Parameters[0].String() != "x" {
+		t.Fatalf("parameter is not 'x'. got=%q", macro.Parameters[0])
+	}
+	if macro.Parameters[1].String() != "y" {
+		t.Fatalf("parameter is not 'y'. got=%q", macro.Parameters[1])
+	}
+
+	expectedBody := "(x + y)"
+
+	if macro.Body.String() != expectedBody {
+		t.Fatalf("body is not %q. got=%q", expectedBody, macro.Body.String())
+	}
+}
+
+func testParseProgram(input string) *ast.Program {
+	l := lexer.New(input)
+	p := parser.New(l)
+	return p.ParseProgram()
+}
+
+func TestExpandMacros(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`
+			player infixExpression = macro() { quote(1 + 2); };
+
+			infixExpression();
+			`,
+			`(1 + 2)`,
+		},
+		{
+			`
+			player reverse = macro(a, b) { quote(unquote(b) - unquote(a)); };
+
+			reverse(2 + 2, 10 - 5);
+			`,
+			`(10 - 5) - (2 + 2)`,
+		},
+		{
+			`
+			player unless = macro(condition, consequence, alternative) {
+				quote(appeal (!(unquote(condition))) {
+					unquote(consequence);
+				} appealrejected {
+					unquote(alternative);
+				});
+			};
+
+			unless(10 > 5, gambhir("not", "greater"), rohit("greater"));
+			`,
+			`appeal (!(10 > 5)) { gambhir("not", "greater") } appealrejected { rohit("greater") }`,
+		},
+		{
+			`
+			player overs = macro() { 6 * 20 };
+
+			overs();
+			`,
+			`120`,
+		},
+	}
+
+	for _, tt := range tests {
+		expected := testParseProgram(tt.expected)
+		program := testParseProgram(tt.input)
+
+		env := object.NewEnvironment()
+		DefineMacros(program, env)
+		expanded, misfield := ExpandMacros(program, env)
+		if misfield != nil {
+			t.Fatalf("unexpected misfield: %s", misfield.Inspect())
+		}
+
+		if !ast.Equal(expanded, expected) {
+			t.Errorf("not equal. want=%q, got=%q", expected.String(), expanded.String())
+		}
+	}
+}
+
+func TestExpandMacrosMisfields(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{
+			`player m = macro(a) { quote(unquote(a)) }; m(1, 2);`,
+			"wrong number of arguments to macro m. got=2, want=1",
+		},
+		{
+			`player m = macro() { field(x) { x } }; m();`,
+			"macro m must return a quote, got FIELD",
+		},
+		{
+			`player m = macro() { nope }; m();`,
+			"identifier not found: nope",
+		},
+	}
+
+	for _, tt := range tests {
+		program := testParseProgram(tt.input)
+		env := object.NewEnvironment()
+		DefineMacros(program, env)
+
+		_, misfield := ExpandMacros(program, env)
+		if misfield == nil {
+			t.Errorf("expected misfield for %q", tt.input)
+			continue
+		}
+		if misfield.Message != tt.expectedMessage {
+			t.Errorf("wrong message. want=%q, got=%q", tt.expectedMessage, misfield.Message)
+		}
+	}
+}
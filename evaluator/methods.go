@@ -0,0 +1,68 @@
+package evaluator
+
+import "CricLang/object"
+
+// methodNames lists the builtins each type can call with dot syntax. The
+// value before the dot becomes the builtin's first argument, so
+// s.split(",") is split(s, ",").
+var methodNames = map[object.ObjectType][]string{
+	object.STRING_OBJ: {
+		"len", "split", "contains", "startsWith", "endsWith", "upper", "lower",
+		"trim", "replace", "repeat", "substring",
+	},
+	object.ARRAY_OBJ: {
+		"len", "first", "last", "rest", "push", "concat", "slice", "reverse",
+		"join", "map", "filter", "reduce", "sort", "any", "all", "findIndex",
+		"min", "max",
+	},
+	object.HASH_OBJ: {"len", "keys", "values", "has"},
+}
+
+// methodTables maps each type to its methods by name. It is filled in once
+// the builtins are registered.
+var methodTables = map[object.ObjectType]map[string]*object.Builtin{}
+
+func registerMethods() {
+	for typ, names := range methodNames {
+		table := map[string]*object.Builtin{}
+		for _, name := range names {
+			table[name] = builtins[name]
+		}
+		methodTables[typ] = table
+	}
+}
+
+// evalDotExpression reads a field or method of a squad instance, or a
+// method from the table for a builtin type.
+func evalDotExpression(left object.Object, name string) object.Object {
+	switch left := left.(type) {
+	case *object.Instance:
+		if value, ok := left.Fields.Get(name); ok {
+			return value
+		}
+		if method, ok := left.Squad.Method(name); ok {
+			return bindMethod(left, method)
+		}
+		return newMisfield("%s has no field or method %s", left.Squad.Name, name)
+	default:
+		table, ok := methodTables[left.Type()]
+		if !ok {
+			return newMisfield("dot operator team not allowed: %s", left.Type())
+		}
+		method, ok := table[name]
+		if !ok {
+			return newMisfield("%s has no method %s", left.Type(), name)
+		}
+		return bindBuiltin(left, method)
+	}
+}
+
+// bindBuiltin returns builtin with receiver already passed as its first
+// argument.
+func bindBuiltin(receiver object.Object, builtin *object.Builtin) *object.Builtin {
+	return &object.Builtin{
+		Fn: func(ctx *object.CallContext, args ...object.Object) object.Object {
+			return builtin.Fn(ctx, append([]object.Object{receiver}, args...)...)
+		},
+	}
+}
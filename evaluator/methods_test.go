@@ -0,0 +1,52 @@
+package evaluator
+
+import "testing"
+
+func TestMethodCalls(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"a,b,c".split(",").reverse().join("-")`, "c-b-a"},
+		{`" Kohli ".trim().upper()`, "KOHLI"},
+		{`"Kohli".len()`, 5},
+		{`"Kohli".substring(0, 3)`, "Koh"},
+		{`"Kohli".contains("oh")`, true},
+		{`"4".repeat(3)`, "444"},
+		{`[1, 2, 3].map(field(x) { x * 2 })`, []int{2, 4, 6}},
+		{`[4, 1, 6, 0].filter(field(x) { x > 0 }).sort()`, []int{1, 4, 6}},
+		{`[4, 1, 6].reduce(field(acc, x) { acc + x }, 0)`, 11},
+		{`[1, 2].push(3).concat([4]).len()`, 4},
+		{`[82, 40, 113].max()`, 113},
+		{`player runs = [1, 4, 6]; runs.first() + runs.last()`, 7},
+		{`{"runs": 82, "balls": 53}.keys().join(" ")`, "runs balls"},
+		{`{"runs": 82}.has("balls")`, false},
+		{`player card = {"runs": 82}; card.values()`, []int{82}},
+		{`player split = 1; "a b".split(" ").len()`, 2},
+		{`player m = "a-b".split; m("-").join("+")`, "a+b"},
+		{`"abc".reverse()`, "STRING has no method reverse"},
+		{`[1].upper()`, "ARRAY has no method upper"},
+		{`{}.runs`, "HASH has no method runs"},
+		{`5.len()`, "dot operator team not allowed: INTEGER"},
+		{`"abc".split()`, "wrong number of arguments. got=1, want=2"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if expected, ok := tt.expected.(bool); ok {
+			testBooleanObject(t, evaluated, expected)
+			continue
+		}
+		testBuiltinResult(t, tt.input, evaluated, tt.expected)
+	}
+}
+
+func TestMethodTablesNameBuiltins(t *testing.T) {
+	for typ, names := range methodNames {
+		for _, name := range names {
+			if methodTables[typ][name] == nil {
+				t.Errorf("%s method %s is not a builtin", typ, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package evaluator
+
+import (
+	"CricLang/ast"
+	"CricLang/lexer"
+	"CricLang/object"
+	"CricLang/parser"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func evalImport(node *ast.CallExpression, env *object.Environment) object.Object {
+	if len(node.Arguments) != 1 {
+		return newMisfield("wrong number of arguments to import. got=%d, want=1", len(node.Arguments))
+	}
+
+	arg := Eval(node.Arguments[0], env)
+	if isMisfield(arg) {
+		return arg
+	}
+	name, ok := arg.(*object.String)
+	if !ok {
+		return newMisfield("import path must be STRING, got %s", arg.Type())
+	}
+
+	path, misfield := resolveImport(name.Value, env)
+	if misfield != nil {
+		return misfield
+	}
+
+	return importModule(path, env.Runtime())
+}
+
+// resolveImport finds the file an import refers to. Relative paths are
+// tried next to the importing file first and then in each include path
+// directory, in order. The file must lie inside the importing file's
+// directory, an include path directory or one of the runtime's FileRoots,
+// so that import can't read what the file builtins aren't allowed to.
+func resolveImport(name string, env *object.Environment) (string, *object.Misfield) {
+	runtime := env.Runtime()
+	candidates := []string{name}
+	if !filepath.IsAbs(name) {
+		candidates = []string{filepath.Join(env.Dir(), name)}
+		for _, dir := range runtime.IncludePath {
+			candidates = append(candidates, filepath.Join(dir, name))
+		}
+	}
+
+	roots := append([]string{env.Dir()}, runtime.IncludePath...)
+	roots = append(roots, runtime.FileRoots...)
+
+	for _, candidate := range candidates {
+		info, err := os.Stat(candidate)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		resolved, ok := resolveFilePath(candidate, env.Dir(), roots)
+		if !ok {
+			return "", newMisfield("import: %s is outside the allowed directories", name)
+		}
+		return resolved, nil
+	}
+	return "", newMisfield("module not found: %s", name)
+}
+
+func importModule(path string, runtime *object.Runtime) object.Object {
+	if module, ok := runtime.Modules[path]; ok {
+		return module
+	}
+
+	for i, loading := range runtime.Loading {
+		if loading == path {
+			cycle := append([]string{}, runtime.Loading[i:]...)
+			cycle = append(cycle, path)
+			return newMisfield("import cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	runtime.Loading = append(runtime.Loading, path)
+	defer func() { runtime.Loading = runtime.Loading[:len(runtime.Loading)-1] }()
+
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return newMisfield("cannot read module %s: %s", path, err)
+	}
+
+	p := parser.New(lexer.New(string(source)))
+	program := p.ParseProgram()
+	if diagnostics := p.Diagnostics(); len(diagnostics) != 0 {
+		// only the position: the parser's messages quote the source, which
+		// isn't ours to show if the file turns out not to be CricLang
+		first := diagnostics[0]
+		return newMisfield("cannot parse module %s: syntax error at %d:%d", path, first.Line, first.Column)
+	}
+
+	dir := filepath.Dir(path)
+	macroEnv := object.NewRuntimeEnvironment(runtime, dir)
+	DefineMacros(program, macroEnv)
+	expanded, misfield := ExpandMacros(program, macroEnv)
+	if misfield != nil {
+		return newMisfield("in module %s: %s", path, misfield.Message)
+	}
+
+	env := object.NewRuntimeEnvironment(runtime, dir)
+	if evaluated := Eval(expanded, env); isMisfield(evaluated) {
+		return newMisfield("in module %s: %s", path, evaluated.(*object.Misfield).Message)
+	}
+
+	module := &object.Module{Path: path, Env: env}
+	runtime.Modules[path] = module
+	return module
+}
+
+func evalModuleIndexExpression(module, index object.Object) object.Object {
+	moduleObject := module.(*object.Module)
+	name := index.(*object.String).Value
+
+	if export, ok := moduleObject.Export(name); ok {
+		return export
+	}
+	return newMisfield("module %s has no player %s", moduleObject.Path, name)
+}
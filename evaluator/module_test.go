@@ -0,0 +1,176 @@
+package evaluator
+
+import (
+	"CricLang/lexer"
+	"CricLang/object"
+	"CricLang/parser"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeModules(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, source := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func testEvalIn(input string, runtime *object.Runtime, dir string) object.Object {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	env := object.NewRuntimeEnvironment(runtime, dir)
+
+	return Eval(program, env)
+}
+
+func TestImport(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"lib/stats.cric": `
+			player helpers = import("helpers.cric");
+			player strikeRate = field(runs, balls) { helpers["percent"](runs, balls) };
+			player format = "T20";
+		`,
+		"lib/helpers.cric": `player percent = field(a, b) { a * 100 / b };`,
+	})
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`player stats = import("lib/stats.cric"); stats["strikeRate"](50, 25)`, 200},
+		{`import("lib/stats.cric")["format"]`, "T20"},
+		{`import("lib/stats.cric") == import("lib/stats.cric")`, true},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEvalIn(tt.input, object.NewRuntime(), dir)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			str, ok := evaluated.(*object.String)
+			if !ok || str.Value != expected {
+				t.Errorf("wrong result for %q. want=%q, got=%s", tt.input, expected, evaluated.Inspect())
+			}
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestImportCachesModules(t *testing.T) {
+	dir := writeModules(t, map[string]string{"a.cric": `player x = 1;`})
+	runtime := object.NewRuntime()
+
+	first := testEvalIn(`import("a.cric")`, runtime, dir)
+	second := testEvalIn(`import("./a.cric")`, runtime, dir)
+	if first != second {
+		t.Errorf("module was loaded twice: %s and %s", first.Inspect(), second.Inspect())
+	}
+
+	module, ok := first.(*object.Module)
+	if !ok {
+		t.Fatalf("object is not Module. got=%T (%+v)", first, first)
+	}
+	if module.Path != filepath.Join(dir, "a.cric") {
+		t.Errorf("wrong module path. got=%q", module.Path)
+	}
+	if len(runtime.Modules) != 1 {
+		t.Errorf("wrong number of cached modules. got=%d", len(runtime.Modules))
+	}
+}
+
+func TestImportIncludePath(t *testing.T) {
+	shared := writeModules(t, map[string]string{"bowling.cric": `player economy = field(runs, overs) { runs / overs };`})
+	local := writeModules(t, map[string]string{})
+
+	runtime := object.NewRuntime()
+	runtime.IncludePath = []string{shared}
+
+	evaluated := testEvalIn(`import("bowling.cric")["economy"](36, 4)`, runtime, local)
+	testIntegerObject(t, evaluated, 9)
+}
+
+func TestImportMisfields(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"a.cric":      `player b = import("b.cric");`,
+		"b.cric":      `player a = import("a.cric");`,
+		"broken.cric": `player = 5;`,
+		"fails.cric":  `player x = 1 + notout;`,
+		"ok.cric":     `player x = 1;`,
+	})
+	a := filepath.Join(dir, "a.cric")
+	b := filepath.Join(dir, "b.cric")
+
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`import("a.cric")`, "in module " + a + ": in module " + b + ": import cycle: " + a + " -> " + b + " -> " + a},
+		{`import("missing.cric")`, "module not found: missing.cric"},
+		{`import(5)`, "import path must be STRING, got INTEGER"},
+		{`import("a.cric", "b.cric")`, "wrong number of arguments to import. got=2, want=1"},
+		{`import("broken.cric")`, "cannot parse module " + filepath.Join(dir, "broken.cric") + ": syntax error at 1:8"},
+		{`import("fails.cric")`, "in module " + filepath.Join(dir, "fails.cric") + ": player type mismatch: INTEGER + BOOLEAN"},
+		{`import("ok.cric")["y"]`, "module " + filepath.Join(dir, "ok.cric") + " has no player y"},
+	}
+
+	for _, tt := range tests {
+		runtime := object.NewRuntime()
+		evaluated := testEvalIn(tt.input, runtime, dir)
+
+		errObj, ok := evaluated.(*object.Misfield)
+		if !ok {
+			t.Errorf("no error object returned for %q. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong error message.\nexpected=%q\ngot=     %q", tt.expectedMessage, errObj.Message)
+		}
+		if len(runtime.Loading) != 0 {
+			t.Errorf("loading stack not unwound for %q: %v", tt.input, runtime.Loading)
+		}
+	}
+}
+
+func TestImportStaysInsideAllowedDirectories(t *testing.T) {
+	root := writeModules(t, map[string]string{
+		"main/lib.cric":   `player x = 1;`,
+		"shared/lib.cric": `player x = 2;`,
+		"secret.txt":      `hunter2`,
+	})
+	main := filepath.Join(root, "main")
+	secret := filepath.Join(root, "secret.txt")
+	if err := os.Symlink(secret, filepath.Join(main, "link.cric")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		input     string
+		fileRoots []string
+		expected  interface{}
+	}{
+		{`import("lib.cric")["x"]`, nil, 1},
+		{`import("../secret.txt")`, nil, "import: ../secret.txt is outside the allowed directories"},
+		{`import("` + secret + `")`, nil, "import: " + secret + " is outside the allowed directories"},
+		{`import("link.cric")`, nil, "import: link.cric is outside the allowed directories"},
+		{`import("../shared/lib.cric")`, nil, "import: ../shared/lib.cric is outside the allowed directories"},
+		{`import("../shared/lib.cric")["x"]`, []string{filepath.Join(root, "shared")}, 2},
+	}
+
+	for _, tt := range tests {
+		runtime := object.NewRuntime()
+		runtime.FileRoots = tt.fileRoots
+		testBuiltinResult(t, tt.input, testEvalIn(tt.input, runtime, main), tt.expected)
+	}
+}
@@ -0,0 +1,116 @@
+package evaluator
+
+import (
+	"CricLang/ast"
+	"CricLang/object"
+	"fmt"
+)
+
+// matchPattern binds the names in pattern to the parts of value they stand
+// for. It returns an empty mismatch when value fits the pattern, and
+// otherwise says what didn't fit. The misfield is only set when a literal in
+// the pattern can't be evaluated.
+func matchPattern(pattern ast.Expression, value object.Object, env *object.Environment) (string, object.Object) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		if pattern.Value != "_" {
+			env.Set(pattern.Value, value)
+		}
+		return "", nil
+	case *ast.ArrayLiteral:
+		return matchArrayPattern(pattern, value, env)
+	case *ast.HashLiteral:
+		return matchHashPattern(pattern, value, env)
+	default:
+		literal := Eval(pattern, env)
+		if isMisfield(literal) {
+			return "", literal
+		}
+		if literal.Type() != value.Type() || evalInfixExpression("==", literal, value) != NOT_OUT {
+			return fmt.Sprintf("expected %s, got %s", literal.Inspect(), value.Inspect()), nil
+		}
+		return "", nil
+	}
+}
+
+// destructure binds the names in a player's or a field parameter's pattern,
+// and turns a mismatch into a misfield. what names the value in the message.
+func destructure(pattern ast.Expression, value object.Object, env *object.Environment, what string) object.Object {
+	mismatch, misfield := matchPattern(pattern, value, env)
+	if misfield != nil {
+		return misfield
+	}
+	if mismatch != "" {
+		return newMisfield("cannot destructure %s: %s", what, mismatch)
+	}
+	return nil
+}
+
+func matchArrayPattern(pattern *ast.ArrayLiteral, value object.Object, env *object.Environment) (string, object.Object) {
+	array, ok := value.(*object.Array)
+	if !ok {
+		return fmt.Sprintf("expected an ARRAY, got %s", value.Type()), nil
+	}
+
+	elements := pattern.Elements
+	var rest *ast.RestElement
+	if n := len(elements); n > 0 {
+		if r, ok := elements[n-1].(*ast.RestElement); ok {
+			rest = r
+			elements = elements[:n-1]
+		}
+	}
+
+	switch {
+	case rest == nil && len(array.Elements) != len(elements):
+		return fmt.Sprintf("expected %d elements, got %d", len(elements), len(array.Elements)), nil
+	case len(array.Elements) < len(elements):
+		return fmt.Sprintf("expected at least %d elements, got %d", len(elements), len(array.Elements)), nil
+	}
+
+	for i, el := range elements {
+		if mismatch, misfield := matchPattern(el, array.Elements[i], env); mismatch != "" || misfield != nil {
+			return mismatch, misfield
+		}
+	}
+
+	if rest != nil && rest.Name.Value != "_" {
+		left := make([]object.Object, len(array.Elements)-len(elements))
+		copy(left, array.Elements[len(elements):])
+		env.Set(rest.Name.Value, &object.Array{Elements: left})
+	}
+	return "", nil
+}
+
+// matchHashPattern needs every key in the pattern to be present; keys the
+// pattern doesn't mention are ignored. A squad instance matches by its
+// fields.
+func matchHashPattern(pattern *ast.HashLiteral, value object.Object, env *object.Environment) (string, object.Object) {
+	var hash *object.Hash
+	switch value := value.(type) {
+	case *object.Hash:
+		hash = value
+	case *object.Instance:
+		hash = value.Fields
+	default:
+		return fmt.Sprintf("expected a HASH, got %s", value.Type()), nil
+	}
+
+	for _, pair := range pattern.Pairs {
+		key, ok := pair.Key.(*ast.StringLiteral)
+		if !ok {
+			return "", newMisfield("hash pattern keys must be strings, got %s", pair.Key.String())
+		}
+		field, ok := hash.Get(key.Value)
+		if !ok {
+			if instance, isInstance := value.(*object.Instance); isInstance {
+				return fmt.Sprintf("%s has no field %s", instance.Squad.Name, key.Value), nil
+			}
+			return fmt.Sprintf("no key %s in %s", key.Value, value.Inspect()), nil
+		}
+		if mismatch, misfield := matchPattern(pair.Value, field, env); mismatch != "" || misfield != nil {
+			return mismatch, misfield
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,51 @@
+package evaluator
+
+import "testing"
+
+func TestPlayerDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`player pair = ["Rohit", "Gill"]; player [striker, nonStriker] = pair; nonStriker`, "Gill"},
+		{`player [a, [b, c]] = [1, [2, 3]]; a + b + c`, 6},
+		{`player [first, ...rest] = [1, 2, 3]; rest`, []int{2, 3}},
+		{`player [first, ...rest] = [1]; rest`, []int{}},
+		{`player [_, second, ..._] = [1, 2, 3, 4]; second`, 2},
+		{`player {runs, balls} = {"runs": 82, "balls": 53, "fours": 6}; runs * 100 / balls`, 154},
+		{`player {"batter": {name}, "overs": [o, ...more]} = {"batter": {"name": "Kohli"}, "overs": [1, 2]}; name`, "Kohli"},
+		{`player ["W", kind] = ["W", "bowled"]; kind`, "bowled"},
+		{`squad Batter(name, runs) {}; player {name, runs} = Batter("Kohli", 82); runs`, 82},
+		{`player [a, b] = [1, 2, 3]`, "cannot destructure [1, 2, 3]: expected 2 elements, got 3"},
+		{`player [a, b, ...c] = [1]`, "cannot destructure [1]: expected at least 2 elements, got 1"},
+		{`player [a, [b]] = [1, 2]`, "cannot destructure [1, 2]: expected an ARRAY, got INTEGER"},
+		{`player {runs} = [82]`, "cannot destructure [82]: expected a HASH, got ARRAY"},
+		{`player {runs, balls} = {"runs": 82}`, "cannot destructure {runs: 82}: no key balls in {runs: 82}"},
+		{`player ["W", kind] = ["wd", 1]`, "cannot destructure [wd, 1]: expected W, got wd"},
+		{`squad Batter(name) {}; player {runs} = Batter("Kohli")`, "cannot destructure Batter{name: Kohli}: Batter has no field runs"},
+		{`player [a] = nobody`, "identifier not found: nobody"},
+	}
+
+	for _, tt := range tests {
+		testBuiltinResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestFieldParameterDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`player sr = field({runs, balls}) { runs * 100 / balls }; sr({"runs": 82, "balls": 53})`, 154},
+		{`player swap = field([a, b]) { [b, a] }; swap([1, 2])`, []int{2, 1}},
+		{`player f = field(x, [y, ...ys], {z}) { x + y + len(ys) + z }; f(1, [2, 3, 4], {"z": 5})`, 10},
+		{`[[1, 2], [3, 4]].map(field([a, b]) { a * b })`, []int{2, 12}},
+		{`player f = field([a, b]) { a }; f([1])`, "cannot destructure argument 1 to field: expected 2 elements, got 1"},
+		{`player f = field(x, {runs}) { runs }; f(1, {})`, "cannot destructure argument 2 to field: no key runs in {}"},
+		{`player f = field([a]) { a }; f()`, "wrong number of arguments to field. got=0, want=1"},
+	}
+
+	for _, tt := range tests {
+		testBuiltinResult(t, tt.input, testEval(tt.input), tt.expected)
+	}
+}
@@ -0,0 +1,123 @@
+package evaluator
+
+import (
+	"CricLang/ast"
+	"CricLang/object"
+	"CricLang/token"
+	"fmt"
+)
+
+func quote(node ast.Node, env *object.Environment) object.Object {
+	// work on a copy so unquoting never rewrites the program itself, which
+	// matters when the same quote runs more than once
+	node = ast.Clone(node)
+
+	var misfield *object.Misfield
+	node = evalUnquoteCalls(node, env, &misfield)
+	if misfield != nil {
+		return misfield
+	}
+	return &object.Quote{Node: node}
+}
+
+func evalUnquoteCalls(quoted ast.Node, env *object.Environment, misfield **object.Misfield) ast.Node {
+	return ast.Modify(quoted, func(node ast.Node) ast.Node {
+		if !isUnquoteCall(node) {
+			return node
+		}
+
+		call, ok := node.(*ast.CallExpression)
+		if !ok || len(call.Arguments) != 1 {
+			return node
+		}
+
+		unquoted := Eval(call.Arguments[0], env)
+		if isMisfield(unquoted) {
+			if *misfield == nil {
+				*misfield = unquoted.(*object.Misfield)
+			}
+			return node
+		}
+
+		converted, ok := convertObjectToASTNode(unquoted)
+		if !ok {
+			if *misfield == nil {
+				*misfield = newMisfield("cannot unquote %s into the quoted program", unquoted.Type())
+			}
+			return node
+		}
+		return converted
+	})
+}
+
+func isUnquoteCall(node ast.Node) bool {
+	callExpression, ok := node.(*ast.CallExpression)
+	if !ok {
+		return false
+	}
+
+	return callExpression.Function.TokenLiteral() == "unquote"
+}
+
+// convertObjectToASTNode turns a value back into source form so it can be
+// spliced into a quoted program. Only values that have a literal syntax can
+// be converted, plus quotes, which already are source.
+func convertObjectToASTNode(obj object.Object) (ast.Node, bool) {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		t := token.Token{Type: token.INT, Literal: fmt.Sprintf("%d", obj.Value)}
+		if obj.Value < 0 {
+			// there are no negative literals; write it the way the parser would
+			t.Literal = fmt.Sprintf("%d", -obj.Value)
+			return &ast.PrefixExpression{
+				Token:    token.Token{Type: token.MINUS, Literal: "-"},
+				Operator: "-",
+				Right:    &ast.IntegerLiteral{Token: t, Value: -obj.Value},
+			}, true
+		}
+		return &ast.IntegerLiteral{Token: t, Value: obj.Value}, true
+	case *object.Boolean:
+		if obj.Value {
+			return &ast.Boolean{Token: token.Token{Type: token.TRUE, Literal: "notout"}, Value: true}, true
+		}
+		return &ast.Boolean{Token: token.Token{Type: token.FALSE, Literal: "out"}, Value: false}, true
+	case *object.String:
+		return &ast.StringLiteral{Token: token.Token{Type: token.STRING, Literal: obj.Value}, Value: obj.Value}, true
+	case *object.Array:
+		elements := []ast.Expression{}
+		for _, el := range obj.Elements {
+			exp, ok := convertObjectToExpression(el)
+			if !ok {
+				return nil, false
+			}
+			elements = append(elements, exp)
+		}
+		return &ast.ArrayLiteral{Token: token.Token{Type: token.LBRACKET, Literal: "["}, Elements: elements}, true
+	case *object.Hash:
+		pairs := []ast.HashPair{}
+		for _, key := range obj.Keys {
+			value, ok := convertObjectToExpression(obj.Pairs[key])
+			if !ok {
+				return nil, false
+			}
+			pairs = append(pairs, ast.HashPair{
+				Key:   &ast.StringLiteral{Token: token.Token{Type: token.STRING, Literal: key}, Value: key},
+				Value: value,
+			})
+		}
+		return &ast.HashLiteral{Token: token.Token{Type: token.LBRACE, Literal: "{"}, Pairs: pairs}, true
+	case *object.Quote:
+		return obj.Node, true
+	default:
+		return nil, false
+	}
+}
+
+func convertObjectToExpression(obj object.Object) (ast.Expression, bool) {
+	node, ok := convertObjectToASTNode(obj)
+	if !ok {
+		return nil, false
+	}
+	exp, ok := node.(ast.Expression)
+	return exp, ok
+}
@@ -0,0 +1,110 @@
+package evaluator
+
+import (
+	"CricLang/ast"
+	"CricLang/object"
+)
+
+func evalSquadStatement(node *ast.SquadStatement, env *object.Environment) object.Object {
+	squad := &object.Squad{Name: node.Name.Value, Methods: map[string]*object.Field{}}
+	if object.IsBuiltinType(squad.Name) {
+		return newMisfield("squad %s has the name of a builtin type", squad.Name)
+	}
+
+	if node.Parent != nil {
+		parent := evalIdentifier(node.Parent, env)
+		if isMisfield(parent) {
+			return parent
+		}
+		parentSquad, ok := parent.(*object.Squad)
+		if !ok {
+			return newMisfield("squad %s can only extend a SQUAD, got %s", squad.Name, parent.Type())
+		}
+		squad.Parent = parentSquad
+	}
+
+	for _, f := range node.Fields {
+		if containsString(squad.AllFields(), f.Value) {
+			return newMisfield("squad %s declares field %s twice", squad.Name, f.Value)
+		}
+		if _, ok := squad.Method(f.Value); ok {
+			return newMisfield("squad %s: field %s has the name of a method", squad.Name, f.Value)
+		}
+		squad.Fields = append(squad.Fields, f.Value)
+	}
+
+	for _, stmt := range node.Body.Statements {
+		method, ok := stmt.(*ast.PlayerStatement)
+		if !ok || method.Name == nil {
+			return newMisfield("squad %s can only declare methods, got %s", squad.Name, stmt.String())
+		}
+		name := method.Name.Value
+		if containsString(squad.AllFields(), name) {
+			return newMisfield("squad %s: method %s has the name of a field", squad.Name, name)
+		}
+		if _, ok := squad.Methods[name]; ok {
+			return newMisfield("squad %s declares method %s twice", squad.Name, name)
+		}
+
+		value := Eval(method.Value, env)
+		if isMisfield(value) {
+			return value
+		}
+		fn, ok := value.(*object.Field)
+		if !ok {
+			return newMisfield("method %s of squad %s must be FIELD, got %s", name, squad.Name, value.Type())
+		}
+		squad.Methods[name] = fn
+	}
+
+	env.Set(squad.Name, squad)
+	return nil
+}
+
+// newInstance is what calling a squad does: each argument fills the field
+// in the same position, inherited fields first.
+func newInstance(squad *object.Squad, args []object.Object) object.Object {
+	fields := squad.AllFields()
+	if len(args) != len(fields) {
+		return newMisfield("wrong number of arguments to squad %s. got=%d, want=%d", squad.Name, len(args), len(fields))
+	}
+
+	instance := &object.Instance{Squad: squad, Fields: object.NewHash()}
+	for i, name := range fields {
+		instance.Fields.Set(name, args[i])
+	}
+	return instance
+}
+
+// bindMethod returns method as a field that sees instance as `self`.
+func bindMethod(instance *object.Instance, method *object.Field) *object.Field {
+	env := object.NewEnclosedEnvironment(method.Env)
+	env.Set("self", instance)
+	return &object.Field{Parameters: method.Parameters, Body: method.Body, Env: env}
+}
+
+func evalAssignExpression(node *ast.AssignExpression, env *object.Environment) object.Object {
+	target, ok := node.Target.(*ast.DotExpression)
+	if !ok {
+		return newMisfield("cannot assign to %s", node.Target.String())
+	}
+
+	left := Eval(target.Left, env)
+	if isMisfield(left) {
+		return left
+	}
+	value := Eval(node.Value, env)
+	if isMisfield(value) {
+		return value
+	}
+
+	instance, ok := left.(*object.Instance)
+	if !ok {
+		return newMisfield("cannot set %s on %s", target.Name.Value, left.Type())
+	}
+	if _, ok := instance.Fields.Get(target.Name.Value); !ok {
+		return newMisfield("%s has no field %s", instance.Squad.Name, target.Name.Value)
+	}
+	instance.Fields.Set(target.Name.Value, value)
+	return value
+}
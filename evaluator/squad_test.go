@@ -0,0 +1,113 @@
+package evaluator
+
+import (
+	"CricLang/object"
+	"testing"
+)
+
+const squadSetup = `
+squad Batter(name, runs, balls) {
+	player strikeRate = field() { self.runs * 100 / self.balls };
+	player face = field(runs) {
+		self.runs = self.runs + runs;
+		self.balls = self.balls + 1;
+		self
+	};
+	player describe = field() { self.name + " " + self.role() };
+	player role = field() { "batter" };
+};
+squad Opener(partner) extends Batter {
+	player role = field() { "opener with " + self.partner };
+};
+player kohli = Batter("Kohli", 82, 53);
+player rohit = Opener("Rohit", 40, 20, "Gill");
+`
+
+func TestSquads(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`kohli.runs`, 82},
+		{`kohli.name`, "Kohli"},
+		{`kohli.strikeRate()`, 154},
+		{`kohli.face(4); kohli.face(6); kohli.runs`, 92},
+		{`kohli.face(4).face(2).balls`, 55},
+		{`kohli.runs = 100; kohli.runs`, 100},
+		{`kohli.runs = kohli.balls = 0; kohli.runs`, 0},
+		{`player sr = kohli.strikeRate; kohli.runs = 106; sr()`, 200},
+		{`rohit.partner`, "Gill"},
+		{`rohit.strikeRate()`, 200},
+		{`rohit.describe()`, "Rohit opener with Gill"},
+		{`kohli.describe()`, "Kohli batter"},
+		{`player copy = kohli; copy.runs = 0; kohli.runs`, 0},
+		{`kohli.average`, "Batter has no field or method average"},
+		{`kohli.average = 50`, "Batter has no field average"},
+		{`Batter("Kohli", 82)`, "wrong number of arguments to squad Batter. got=2, want=3"},
+		{`Opener("Rohit", 40, 20)`, "wrong number of arguments to squad Opener. got=3, want=4"},
+		{`kohli.face()`, "wrong number of arguments to field. got=0, want=1"},
+		{`player card = {"runs": 1}; card.runs`, "HASH has no method runs"},
+		{`player n = 5; n.runs = 1`, "cannot set runs on INTEGER"},
+		{`squad Bad extends kohli {}`, "squad Bad can only extend a SQUAD, got Batter"},
+		{`kohli + 1`, "player type mismatch: Batter + INTEGER"},
+		{`len(rohit)`, "argument to `len` not supported, got Opener"},
+		{`squad INTEGER(v) {}; INTEGER(1) + 1`, "squad INTEGER has the name of a builtin type"},
+		{`squad ARRAY(v) {}`, "squad ARRAY has the name of a builtin type"},
+		{`squad HASH {}`, "squad HASH has the name of a builtin type"},
+		{`squad Hash(v) {}; Hash(1).v`, 1},
+		{`squad Bad(runs) extends Batter {}`, "squad Bad declares field runs twice"},
+		{`squad Bad(x, x) {}`, "squad Bad declares field x twice"},
+		{`squad Bad(role) extends Batter {}`, "squad Bad: field role has the name of a method"},
+		{`squad Bad(x) { player x = field() { 1 }; }`, "squad Bad: method x has the name of a field"},
+		{`squad Bad { player f = field() { 1 }; player f = field() { 2 }; }`, "squad Bad declares method f twice"},
+		{`squad Bad extends Nobody {}`, "identifier not found: Nobody"},
+	}
+
+	for _, tt := range tests {
+		input := squadSetup + tt.input
+		testBuiltinResult(t, input, testEval(input), tt.expected)
+	}
+}
+
+func TestSquadTypesAndInspect(t *testing.T) {
+	tests := []struct {
+		input       string
+		expectedTyp object.ObjectType
+		expected    string
+	}{
+		{`kohli`, "Batter", "Batter{name: Kohli, runs: 82, balls: 53}"},
+		{`rohit`, "Opener", "Opener{name: Rohit, runs: 40, balls: 20, partner: Gill}"},
+		{`Batter`, object.SQUAD_OBJ, "squad Batter(name, runs, balls)"},
+		{`Opener`, object.SQUAD_OBJ, "squad Opener(partner) extends Batter"},
+		{`kohli.face(1)`, "Batter", "Batter{name: Kohli, runs: 83, balls: 54}"},
+		{`kohli.name = kohli; kohli`, "Batter", "Batter{name: Batter{...}, runs: 82, balls: 53}"},
+		{`kohli.runs = [rohit, {"me": kohli}]; rohit.partner = kohli; kohli`, "Batter",
+			"Batter{name: Kohli, runs: [Opener{name: Rohit, runs: 40, balls: 20, partner: Batter{...}}, {me: Batter{...}}], balls: 53}"},
+		{`[kohli, kohli]`, object.ARRAY_OBJ, "[Batter{name: Kohli, runs: 82, balls: 53}, Batter{name: Kohli, runs: 82, balls: 53}]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(squadSetup + tt.input)
+		if evaluated.Type() != tt.expectedTyp {
+			t.Errorf("%s: wrong type. want=%s, got=%s", tt.input, tt.expectedTyp, evaluated.Type())
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: wrong Inspect.\nwant=%q\ngot= %q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestSquadInstancesCompareByIdentity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{`kohli == kohli`, true},
+		{`kohli == Batter("Kohli", 82, 53)`, false},
+		{`player same = kohli; same == kohli`, true},
+	}
+
+	for _, tt := range tests {
+		testBooleanObject(t, testEval(squadSetup+tt.input), tt.expected)
+	}
+}
@@ -0,0 +1,40 @@
+package evaluator
+
+import (
+	"CricLang/ast"
+	"CricLang/object"
+)
+
+// evalUmpireExpression tries each arm in order. An arm's bindings live in an
+// environment of their own, so a pattern that matches part of the way and
+// then fails leaves nothing behind for the next arm.
+func evalUmpireExpression(node *ast.UmpireExpression, env *object.Environment) object.Object {
+	subject := Eval(node.Subject, env)
+	if isMisfield(subject) {
+		return subject
+	}
+
+	for _, arm := range node.Arms {
+		armEnv := object.NewEnclosedEnvironment(env)
+
+		mismatch, misfield := matchPattern(arm.Pattern, subject, armEnv)
+		if misfield != nil {
+			return misfield
+		}
+		if mismatch != "" {
+			continue
+		}
+
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isMisfield(guard) {
+				return guard
+			}
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+		return Eval(arm.Body, armEnv)
+	}
+	return newMisfield("umpire: no arm matches %s", subject.Inspect())
+}
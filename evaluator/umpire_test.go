@@ -0,0 +1,57 @@
+package evaluator
+
+import "testing"
+
+const umpireSetup = `
+player call = field(ball) {
+	umpire (ball) {
+		4 -> "four";
+		6 -> "six";
+		0 -> "dot";
+		-1 -> "no run";
+		["W", kind] -> "out " + kind;
+		["wd", ...extra] -> sprintf("wide %d", len(extra));
+		{"kind": "nb", runs} -> sprintf("no ball %d", runs);
+		_ -> "other"
+	}
+};
+`
+
+func TestUmpireExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`call(4)`, "four"},
+		{`call(6)`, "six"},
+		{`call(0)`, "dot"},
+		{`call(-1)`, "no run"},
+		{`call(["W", "caught"])`, "out caught"},
+		{`call(["W"])`, "other"},
+		{`call(["W", "lbw", "extra"])`, "other"},
+		{`call(["wd"])`, "wide 0"},
+		{`call(["wd", 1, 2])`, "wide 2"},
+		{`call({"kind": "nb", "runs": 4, "free hit": notout})`, "no ball 4"},
+		{`call({"kind": "nb"})`, "other"},
+		{`umpire (7) { n appeal (n > 6) -> "overthrows"; n -> "runs" }`, "overthrows"},
+		{`umpire (2) { n appeal (n > 6) -> "overthrows"; n -> "runs" }`, "runs"},
+		{`call(3)`, "other"},
+		{`call("4")`, "other"},
+		{`umpire ([1, [2, 3]]) { [a, [b, c]] -> a + b + c }`, 6},
+		{`umpire ([1, 2, 3]) { [first, ...rest] -> rest }`, []int{2, 3}},
+		{`umpire ([1, 2, 3]) { [..._] -> 1 }`, 1},
+		{`umpire (notout) { out -> 0; notout -> 1 }`, 1},
+		{`player x = 1; umpire (2) { x -> x }; x`, 1},
+		{`umpire ([1, 2]) { [a, 3] -> a; [_, b] -> a }`, "identifier not found: a"},
+		{`umpire (5) { 4 -> 1; 6 -> 2 }`, "umpire: no arm matches 5"},
+		{`umpire ([1, 2]) { [x] -> x }`, "umpire: no arm matches [1, 2]"},
+		{`umpire (1) { x appeal (x > "a") -> x }`, "player type mismatch: INTEGER > STRING"},
+		{`umpire (nobody) { _ -> 1 }`, "identifier not found: nobody"},
+		{`squad Batter(name, runs) {}; umpire (Batter("Kohli", 82)) { {name, runs} -> sprintf("%s%d", name, runs) }`, "Kohli82"},
+	}
+
+	for _, tt := range tests {
+		input := umpireSetup + tt.input
+		testBuiltinResult(t, input, testEval(input), tt.expected)
+	}
+}
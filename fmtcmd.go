@@ -0,0 +1,85 @@
+package main
+
+import (
+	"CricLang/formatter"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func fmtCommand(args []string) int {
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	write := flags.Bool("w", false, "write result to (source) file instead of stdout")
+	diff := flags.Bool("d", false, "display diffs instead of rewriting files")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: criclang fmt [-w] [-d] [file.cric ...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		if *write {
+			fmt.Fprintln(os.Stderr, "criclang fmt: cannot use -w with standard input")
+			return 2
+		}
+		src, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "criclang fmt: %s\n", err)
+			return 1
+		}
+		if !formatSource("<standard input>", src, *write, *diff) {
+			return 1
+		}
+		return 0
+	}
+
+	status := 0
+	for _, path := range flags.Args() {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "criclang fmt: %s\n", err)
+			status = 1
+			continue
+		}
+		if !formatSource(path, src, *write, *diff) {
+			status = 1
+		}
+	}
+	return status
+}
+
+func formatSource(path string, src []byte, write, diff bool) bool {
+	formatted, err := formatter.Source(src)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ben Stokes!!!! %s\n%s\n", path, err)
+		return false
+	}
+
+	if diff {
+		os.Stdout.Write(formatter.Diff(path+".orig", path, src, formatted))
+	}
+
+	if write {
+		if bytes.Equal(src, formatted) {
+			return true
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "criclang fmt: %s\n", err)
+			return false
+		}
+		if err := os.WriteFile(path, formatted, info.Mode().Perm()); err != nil {
+			fmt.Fprintf(os.Stderr, "criclang fmt: %s\n", err)
+			return false
+		}
+	}
+
+	if !write && !diff {
+		os.Stdout.Write(formatted)
+	}
+	return true
+}
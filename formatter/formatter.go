@@ -0,0 +1,477 @@
+package formatter
+
+import (
+	"CricLang/ast"
+	"CricLang/lexer"
+	"CricLang/parser"
+	"CricLang/token"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const indentUnit = "    "
+
+// Source parses src and returns it in canonical CricLang form, keeping
+// its comments. Source that doesn't parse is returned unchanged together
+// with an error listing the parser's complaints, and so is source with a
+// comment in the middle of an expression, which can't be kept in place.
+func Source(src []byte) ([]byte, error) {
+	l := lexer.New(string(src))
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return src, errors.New(strings.Join(p.Errors(), "\n"))
+	}
+
+	pr := printProgram(program, l.Comments())
+	if c := pr.stray; c.Line != 0 {
+		return src, fmt.Errorf("%d:%d: a comment inside an expression can't be kept in place; move it above the statement", c.Line, c.Column)
+	}
+	return pr.out.Bytes(), nil
+}
+
+// Program prints program as canonical CricLang. Comments are placed back
+// next to the statements they were written beside, using the positions
+// recorded by the lexer; one in the middle of an expression ends up on its
+// own line after the statement.
+func Program(program *ast.Program, comments []token.Token) string {
+	return printProgram(program, comments).out.String()
+}
+
+func printProgram(program *ast.Program, comments []token.Token) *printer {
+	pr := &printer{comments: comments}
+	pr.statements(program.Statements, token.Token{})
+	pr.flushComments(token.Token{})
+	return pr
+}
+
+// Node prints a single node as canonical CricLang, without comments.
+func Node(node ast.Node) string {
+	pr := &printer{}
+
+	switch node := node.(type) {
+	case *ast.Program:
+		pr.statements(node.Statements, token.Token{})
+	case ast.Statement:
+		pr.statement(node)
+	case ast.Expression:
+		pr.expression(node, parser.LOWEST)
+	}
+	return strings.TrimSuffix(pr.out.String(), "\n")
+}
+
+type printer struct {
+	out    bytes.Buffer
+	indent int
+
+	comments []token.Token
+	lastLine int         // last source line printed, for blank lines and trailing comments
+	stray    token.Token // first comment passed over inside an expression
+}
+
+func (pr *printer) write(s string) {
+	if pr.out.Len() == 0 || pr.out.Bytes()[pr.out.Len()-1] == '\n' {
+		pr.out.WriteString(strings.Repeat(indentUnit, pr.indent))
+	}
+	pr.out.WriteString(s)
+}
+
+func (pr *printer) newline() {
+	pr.out.WriteString("\n")
+}
+
+// see records that t has been printed. A pending comment that comes before
+// t sits inside what is being printed, where it can't be written.
+func (pr *printer) see(t token.Token) {
+	if pr.stray.Line == 0 && t.Line != 0 && len(pr.comments) > 0 && before(pr.comments[0], t) {
+		pr.stray = pr.comments[0]
+	}
+	if t.Line > pr.lastLine {
+		pr.lastLine = t.Line
+	}
+}
+
+// before reports whether a sits earlier in the source than b. A token
+// without a position (b.Line == 0) is treated as the end of the input.
+func before(a, b token.Token) bool {
+	if b.Line == 0 {
+		return true
+	}
+	return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+}
+
+// flushComments writes every pending comment that comes before limit, each
+// on its own line.
+func (pr *printer) flushComments(limit token.Token) {
+	for len(pr.comments) > 0 && before(pr.comments[0], limit) {
+		c := pr.comments[0]
+		pr.comments = pr.comments[1:]
+		pr.blankLineBefore(c.Line)
+		pr.write(c.Literal)
+		pr.newline()
+		pr.see(c)
+	}
+}
+
+// trailingComment writes a comment that shares the line the previous
+// statement ended on, as long as it comes before limit.
+func (pr *printer) trailingComment(limit token.Token) {
+	if len(pr.comments) == 0 {
+		return
+	}
+	c := pr.comments[0]
+	if c.Line != pr.lastLine || !before(c, limit) {
+		return
+	}
+	pr.comments = pr.comments[1:]
+	pr.out.WriteString(" " + c.Literal)
+}
+
+// blankLineBefore keeps at most one blank line where the source had some,
+// but never at the top of the file or straight after an opening brace.
+func (pr *printer) blankLineBefore(line int) {
+	if pr.lastLine == 0 || line <= pr.lastLine+1 {
+		return
+	}
+	written := bytes.TrimRight(pr.out.Bytes(), " \n")
+	if len(written) == 0 || written[len(written)-1] == '{' {
+		return
+	}
+	pr.newline()
+}
+
+// statements prints stmts one per line. end is the token that closes the
+// enclosing block, or the zero token at the top level.
+func (pr *printer) statements(stmts []ast.Statement, end token.Token) {
+	for i, stmt := range stmts {
+		start := statementToken(stmt)
+		pr.flushComments(start)
+		pr.blankLineBefore(start.Line)
+
+		pr.statement(stmt)
+
+		limit := end
+		if i+1 < len(stmts) {
+			limit = statementToken(stmts[i+1])
+		}
+		pr.trailingComment(limit)
+		pr.newline()
+	}
+}
+
+func statementToken(stmt ast.Statement) token.Token {
+	switch stmt := stmt.(type) {
+	case *ast.PlayerStatement:
+		return stmt.Token
+	case *ast.SignalDecisionStatement:
+		return stmt.Token
+	case *ast.ExpressionStatement:
+		return stmt.Token
+	case *ast.BlockStatement:
+		return stmt.Token
+	case *ast.SquadStatement:
+		return stmt.Token
+	}
+	return token.Token{}
+}
+
+func (pr *printer) statement(stmt ast.Statement) {
+	pr.see(statementToken(stmt))
+
+	switch stmt := stmt.(type) {
+	case *ast.PlayerStatement:
+		if stmt.Pattern != nil {
+			pr.write("player ")
+			pr.pattern(stmt.Pattern)
+			pr.write(" = ")
+		} else {
+			pr.write("player " + stmt.Name.Value + " = ")
+		}
+		pr.expression(stmt.Value, parser.LOWEST)
+	case *ast.SignalDecisionStatement:
+		pr.write("signaldecision ")
+		pr.expression(stmt.SignalDecisionValue, parser.LOWEST)
+	case *ast.ExpressionStatement:
+		pr.expression(stmt.Expression, parser.LOWEST)
+	case *ast.BlockStatement:
+		pr.block(stmt)
+	case *ast.SquadStatement:
+		pr.write("squad " + stmt.Name.Value)
+		if len(stmt.Fields) > 0 {
+			pr.write("(" + parameterList(stmt.Fields) + ")")
+		}
+		if stmt.Parent != nil {
+			pr.write(" extends " + stmt.Parent.Value)
+		}
+		pr.write(" ")
+		pr.block(stmt.Body)
+	}
+	pr.write(";")
+}
+
+func (pr *printer) block(block *ast.BlockStatement) {
+	pr.see(block.Token)
+	pr.write("{")
+
+	hasComments := len(pr.comments) > 0 && before(pr.comments[0], block.EndToken)
+	if len(block.Statements) == 0 && !hasComments {
+		pr.write("}")
+		pr.see(block.EndToken)
+		return
+	}
+
+	pr.trailingComment(firstToken(block))
+	pr.newline()
+	pr.indent++
+	pr.statements(block.Statements, block.EndToken)
+	pr.flushComments(block.EndToken)
+	pr.indent--
+	pr.write("}")
+	pr.see(block.EndToken)
+}
+
+func firstToken(block *ast.BlockStatement) token.Token {
+	if len(block.Statements) > 0 {
+		return statementToken(block.Statements[0])
+	}
+	return block.EndToken
+}
+
+// precedence is how tightly exp binds when printed without parentheses.
+func precedence(exp ast.Expression) int {
+	switch exp := exp.(type) {
+	case *ast.InfixExpression:
+		return parser.Precedence(token.TokenType(exp.Operator))
+	case *ast.PrefixExpression:
+		return parser.PREFIX
+	case *ast.AssignExpression:
+		return parser.ASSIGN
+	case *ast.IntegerLiteral:
+		// the optimizer can leave negative literals behind; those print as
+		// a prefix minus
+		if exp.Value < 0 {
+			return parser.PREFIX
+		}
+	}
+	return parser.INDEX + 1
+}
+
+// expression prints exp, wrapping it in parentheses when it binds more
+// loosely than its surroundings require.
+func (pr *printer) expression(exp ast.Expression, min int) {
+	if exp != nil && precedence(exp) < min {
+		pr.write("(")
+		pr.expression(exp, parser.LOWEST)
+		pr.write(")")
+		return
+	}
+
+	switch exp := exp.(type) {
+	case *ast.Identifier:
+		pr.see(exp.Token)
+		pr.write(exp.Value)
+	case *ast.IntegerLiteral:
+		pr.see(exp.Token)
+		pr.write(strconv.FormatInt(exp.Value, 10))
+	case *ast.StringLiteral:
+		pr.see(exp.Token)
+		pr.write(`"` + exp.Value + `"`)
+	case *ast.Boolean:
+		pr.see(exp.Token)
+		if exp.Value {
+			pr.write("notout")
+		} else {
+			pr.write("out")
+		}
+	case *ast.PrefixExpression:
+		pr.write(exp.Operator)
+		pr.expression(exp.Right, parser.PREFIX)
+	case *ast.InfixExpression:
+		prec := precedence(exp)
+		pr.expression(exp.Left, prec)
+		pr.write(" " + exp.Operator + " ")
+		// operators are left associative, so an equally binding right
+		// operand needs parentheses to keep its grouping
+		pr.expression(exp.Right, prec+1)
+	case *ast.AppealIfExpression:
+		pr.write("appeal (")
+		pr.expression(exp.Condition, parser.LOWEST)
+		pr.write(") ")
+		pr.block(exp.Consequence)
+		if exp.Alternative != nil {
+			pr.write(" appealrejected ")
+			pr.block(exp.Alternative)
+		}
+	case *ast.FieldLiteral:
+		pr.write("field(")
+		for i, param := range exp.Parameters {
+			if i > 0 {
+				pr.write(", ")
+			}
+			pr.pattern(param)
+		}
+		pr.write(") ")
+		pr.block(exp.Body)
+	case *ast.MacroLiteral:
+		pr.write("macro(" + parameterList(exp.Parameters) + ") ")
+		pr.block(exp.Body)
+	case *ast.CallExpression:
+		pr.expression(exp.Function, parser.CALL)
+		pr.write("(")
+		pr.expressionList(exp.Arguments)
+		pr.write(")")
+	case *ast.ArrayLiteral:
+		pr.write("[")
+		pr.expressionList(exp.Elements)
+		pr.write("]")
+	case *ast.HashLiteral:
+		pr.write("{")
+		for i, pair := range exp.Pairs {
+			if i > 0 {
+				pr.write(", ")
+			}
+			pr.expression(pair.Key, parser.LOWEST)
+			pr.write(": ")
+			pr.expression(pair.Value, parser.LOWEST)
+		}
+		pr.write("}")
+	case *ast.IndexExpression:
+		pr.expression(exp.Left, parser.INDEX)
+		pr.write("[")
+		pr.expression(exp.Index, parser.LOWEST)
+		pr.write("]")
+	case *ast.DotExpression:
+		pr.expression(exp.Left, parser.INDEX)
+		pr.see(exp.Name.Token)
+		pr.write("." + exp.Name.Value)
+	case *ast.AssignExpression:
+		// assignment is right associative, so only the target needs to
+		// bind more tightly
+		pr.expression(exp.Target, parser.ASSIGN+1)
+		pr.write(" = ")
+		pr.expression(exp.Value, parser.ASSIGN)
+	case *ast.UmpireExpression:
+		pr.umpire(exp)
+	case *ast.RestElement:
+		pr.write("..." + exp.Name.Value)
+	}
+}
+
+// umpire prints one arm per line. Like a block's statements, each arm keeps
+// the comments written above it and the one trailing its line.
+func (pr *printer) umpire(exp *ast.UmpireExpression) {
+	pr.see(exp.Token)
+	pr.write("umpire (")
+	pr.expression(exp.Subject, parser.LOWEST)
+	pr.write(") {")
+
+	first := exp.EndToken
+	if len(exp.Arms) > 0 {
+		first = patternToken(exp.Arms[0].Pattern)
+	}
+	pr.trailingComment(first)
+	pr.newline()
+	pr.indent++
+	for i, arm := range exp.Arms {
+		start := patternToken(arm.Pattern)
+		pr.flushComments(start)
+		pr.blankLineBefore(start.Line)
+
+		pr.see(start)
+		pr.pattern(arm.Pattern)
+		if arm.Guard != nil {
+			pr.write(" appeal (")
+			pr.expression(arm.Guard, parser.LOWEST)
+			pr.write(")")
+		}
+		pr.write(" -> ")
+		pr.expression(arm.Body, parser.LOWEST)
+		pr.write(";")
+
+		limit := exp.EndToken
+		if i+1 < len(exp.Arms) {
+			limit = patternToken(exp.Arms[i+1].Pattern)
+		}
+		pr.trailingComment(limit)
+		pr.newline()
+	}
+	pr.flushComments(exp.EndToken)
+	pr.indent--
+	pr.write("}")
+	pr.see(exp.EndToken)
+}
+
+// patternToken is the first token of an umpire arm's pattern.
+func patternToken(pattern ast.Expression) token.Token {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		return pattern.Token
+	case *ast.IntegerLiteral:
+		return pattern.Token
+	case *ast.StringLiteral:
+		return pattern.Token
+	case *ast.Boolean:
+		return pattern.Token
+	case *ast.PrefixExpression:
+		return pattern.Token
+	case *ast.ArrayLiteral:
+		return pattern.Token
+	case *ast.HashLiteral:
+		return pattern.Token
+	}
+	return token.Token{}
+}
+
+// pattern prints an umpire, player or parameter pattern. A hash pair that binds its own key is
+// written the short way, as {runs}.
+func (pr *printer) pattern(exp ast.Expression) {
+	switch exp := exp.(type) {
+	case *ast.ArrayLiteral:
+		pr.write("[")
+		for i, el := range exp.Elements {
+			if i > 0 {
+				pr.write(", ")
+			}
+			pr.pattern(el)
+		}
+		pr.write("]")
+	case *ast.HashLiteral:
+		pr.write("{")
+		for i, pair := range exp.Pairs {
+			if i > 0 {
+				pr.write(", ")
+			}
+			key, isString := pair.Key.(*ast.StringLiteral)
+			if name, ok := pair.Value.(*ast.Identifier); ok && isString && key.Value == name.Value {
+				pr.write(name.Value)
+				continue
+			}
+			pr.expression(pair.Key, parser.LOWEST)
+			pr.write(": ")
+			pr.pattern(pair.Value)
+		}
+		pr.write("}")
+	default:
+		pr.expression(exp, parser.LOWEST)
+	}
+}
+
+func parameterList(params []*ast.Identifier) string {
+	names := []string{}
+	for _, p := range params {
+		names = append(names, p.Value)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (pr *printer) expressionList(exps []ast.Expression) {
+	for i, e := range exps {
+		if i > 0 {
+			pr.write(", ")
+		}
+		pr.expression(e, parser.LOWEST)
+	}
+}
@@ -0,0 +1,246 @@
+package formatter
+
+import (
+	"CricLang/ast"
+	"CricLang/lexer"
+	"CricLang/parser"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"player x=5;", "player x = 5;\n"},
+		{"1+2*3", "1 + 2 * 3;\n"},
+		{"(1+2)*3", "(1 + 2) * 3;\n"},
+		{"1-(2-3)", "1 - (2 - 3);\n"},
+		{"(1-2)-3", "1 - 2 - 3;\n"},
+		{"-(5+5)", "-(5 + 5);\n"},
+		{"!-a", "!-a;\n"},
+		{"(a+b)(1)", "(a + b)(1);\n"},
+		{"(-a)[0]", "(-a)[0];\n"},
+		{`add("a", [1, 2][0])`, "add(\"a\", [1, 2][0]);\n"},
+		{"signaldecision notout;", "signaldecision notout;\n"},
+		{"appeal(x>1){x}appealrejected{out}", "appeal (x > 1) {\n    x;\n} appealrejected {\n    out;\n};\n"},
+		{"appeal(x){}", "appeal (x) {};\n"},
+		{"field(a,b){a+b}", "field(a, b) {\n    a + b;\n};\n"},
+		{`{"a":1,"b":[2]}["a"]`, "{\"a\": 1, \"b\": [2]}[\"a\"];\n"},
+		{"{}", "{};\n"},
+		{"player f = field(x) { field(y) { x + y } };",
+			"player f = field(x) {\n    field(y) {\n        x + y;\n    };\n};\n"},
+		{"squad A(x,y) extends B{player f=field(){self.x=self.y+1}}",
+			"squad A(x, y) extends B {\n    player f = field() {\n        self.x = self.y + 1;\n    };\n};\n"},
+		{"squad Empty{}", "squad Empty {};\n"},
+		{"(a.b=1)+c.d", "(a.b = 1) + c.d;\n"},
+		{"(-a).b", "(-a).b;\n"},
+		{`player [a,{"runs":r,balls},...c]=x;player f=field([p,q],{runs}){p}`,
+			"player [a, {\"runs\": r, balls}, ...c] = x;\nplayer f = field([p, q], {runs}) {\n    p;\n};\n"},
+		{`player x=umpire(b){4->"four";["W",k,...r] appeal(k=="lbw")->r;{"runs":runs}->runs;_->0}`,
+			"player x = umpire (b) {\n    4 -> \"four\";\n    [\"W\", k, ...r] appeal (k == \"lbw\") -> r;\n    {runs} -> runs;\n    _ -> 0;\n};\n"},
+		{"player u = umpire (b) {\n 1 -> 2;\n _ -> 3;\n};\nplayer c = 2;",
+			"player u = umpire (b) {\n    1 -> 2;\n    _ -> 3;\n};\nplayer c = 2;\n"},
+	}
+
+	for _, tt := range tests {
+		formatted, err := Source([]byte(tt.input))
+		if err != nil {
+			t.Fatalf("Source(%q) failed: %s", tt.input, err)
+		}
+		if string(formatted) != tt.expected {
+			t.Errorf("wrong formatting for %q.\nwant=%q\ngot= %q", tt.input, tt.expected, formatted)
+		}
+	}
+}
+
+func TestFormatComments(t *testing.T) {
+	input := `// header
+
+
+player x = 5;   // trailing
+player f = field(a) { // opens
+	// leading
+	a + x;
+
+	a;
+	// before close
+};
+player u = umpire (b) { // arms
+	// one
+	1 -> 2; // two
+
+	[x] -> x;
+	_ -> 3; // rest
+	// after the arms
+};
+player c = 2;
+// footer`
+
+	expected := `// header
+
+player x = 5; // trailing
+player f = field(a) { // opens
+    // leading
+    a + x;
+
+    a;
+    // before close
+};
+player u = umpire (b) { // arms
+    // one
+    1 -> 2; // two
+
+    [x] -> x;
+    _ -> 3; // rest
+    // after the arms
+};
+player c = 2;
+// footer
+`
+
+	formatted, err := Source([]byte(input))
+	if err != nil {
+		t.Fatalf("Source failed: %s", err)
+	}
+	if string(formatted) != expected {
+		t.Errorf("wrong formatting.\nwant:\n%s\ngot:\n%s", expected, formatted)
+	}
+}
+
+func TestFormatCommentsInsideExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"player h = {\n \"a\": 1, // first\n \"b\": 2\n};", "2:10: a comment inside an expression can't be kept in place; move it above the statement"},
+		{"add(1, // x\n 2)", "1:8: a comment inside an expression can't be kept in place; move it above the statement"},
+		{"player f = field(a) {\n a }; f(1 +\n // y\n 2);", "3:2: a comment inside an expression can't be kept in place; move it above the statement"},
+	}
+
+	for _, tt := range tests {
+		formatted, err := Source([]byte(tt.input))
+		if err == nil || err.Error() != tt.expected {
+			t.Errorf("Source(%q) wrong error. want=%q, got=%v", tt.input, tt.expected, err)
+		}
+		if string(formatted) != tt.input {
+			t.Errorf("source was changed despite the error. got=%q", formatted)
+		}
+	}
+
+	// comments in a block inside an expression are between statements
+	input := "map(arr, field(x) {\n    // double\n    x * 2;\n});\n"
+	formatted, err := Source([]byte(input))
+	if err != nil {
+		t.Fatalf("Source(%q) failed: %s", input, err)
+	}
+	if string(formatted) != input {
+		t.Errorf("wrong formatting.\nwant:\n%s\ngot:\n%s", input, formatted)
+	}
+}
+
+func TestFormatRoundTrips(t *testing.T) {
+	inputs := []string{
+		"player x = -5; player y = 25; player z = field(x, y){ appeal (x * x == y) { signaldecision \"yes\"; } appealrejected { signaldecision \"no\"; }; }; z(x, y)",
+		"a * [1, 2, 3, 4][b * c] * d",
+		"add(a * b[2], b[1], 2 * [1, 2][1])",
+		"3 + 4 * 5 == 3 * 1 + 4 * 5",
+		"5 < 4 != 3 > 4",
+		"(5 + 5) * 2 * (5 + 5)",
+		"1 - (2 - (3 - 4)) - 5",
+		"!(notout == notout)",
+		"field(x) {x;}(5)",
+		"appeal (appeal (a) { b }) { c } appealrejected { d } + 1",
+		"[field() {}, []][0]()",
+		"squad A(x) extends B { player f = field(n) { self.x = self.x + n; self }; }; A(1).f(2).x",
+		"a.x = b.x = c.y * 2",
+		"-a.b + a[0].c(1).d",
+		`"a,b".split(",").map(field(x) { x.upper() }).join("-")`,
+		"player [a, [b, ...c]] = x; player {d, \"e\": [f]} = y; field([g], {h}, i) { g + h + i }",
+		`umpire (f(x)) { -1 -> 0; [a, [b, ...c]] appeal (a > b) -> umpire (c) { [] -> a; _ -> b }; {"k": {"j": j}, m} -> j + m; }`,
+		"player u = umpire (b) {\n 1 -> 2;\n _ -> 3;\n};\nplayer c = 2;\numpire (c) {\n [a] -> umpire (a) {\n  0 -> 1;\n  _ -> 2\n };\n\n _ -> 0\n};\nc",
+	}
+
+	for _, input := range inputs {
+		original := parse(t, input)
+
+		formatted, err := Source([]byte(input))
+		if err != nil {
+			t.Fatalf("Source(%q) failed: %s", input, err)
+		}
+
+		reparsed := parse(t, string(formatted))
+		if !ast.Equal(original, reparsed) {
+			t.Errorf("formatting changed the program %q.\nformatted=%q", input, formatted)
+		}
+
+		again, err := Source(formatted)
+		if err != nil {
+			t.Fatalf("Source of formatted %q failed: %s", formatted, err)
+		}
+		if string(again) != string(formatted) {
+			t.Errorf("formatting is not idempotent.\nfirst= %q\nsecond=%q", formatted, again)
+		}
+	}
+}
+
+func TestNode(t *testing.T) {
+	program := parse(t, "appeal (1 < 2) { signaldecision 10; }")
+	expected := "appeal (1 < 2) {\n    signaldecision 10;\n}"
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	if got := Node(stmt.Expression); got != expected {
+		t.Errorf("wrong output.\nwant=%q\ngot= %q", expected, got)
+	}
+}
+
+func TestSourceErrors(t *testing.T) {
+	input := "player = 5;"
+
+	formatted, err := Source([]byte(input))
+	if err == nil {
+		t.Fatalf("expected an error for %q", input)
+	}
+	if string(formatted) != input {
+		t.Errorf("source was changed despite the error. got=%q", formatted)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	b := "one\n2\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n"
+
+	expected := `--- a
++++ b
+@@ -1,5 +1,5 @@
+ one
+-two
++2
+ three
+ four
+ five
+@@ -8,3 +8,4 @@
+ eight
+ nine
+ ten
++eleven
+`
+
+	if got := string(Diff("a", "b", []byte(a), []byte(b))); got != expected {
+		t.Errorf("wrong diff.\nwant:\n%s\ngot:\n%s", expected, got)
+	}
+
+	if got := Diff("a", "b", []byte(a), []byte(a)); got != nil {
+		t.Errorf("expected no diff for identical input. got=%q", got)
+	}
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors for %q: %s", input, strings.Join(p.Errors(), "; "))
+	}
+	return program
+}
@@ -27,6 +27,11 @@ func TestNextToken(t *testing.T) {
 	"foobar"
 	"foo bar"
 	[1, 2];
+	{"foo": "bar"}
+	squad Opener extends Batter {}
+	self.runs = 4;
+	umpire (x) { [a, ...rest] -> a; }
+	a..b
 	`
 
 	tests := []struct {
@@ -114,6 +119,42 @@ func TestNextToken(t *testing.T) {
 		{token.INT, "2"},
 		{token.RBRACKET, "]"},
 		{token.SEMICOLON, ";"},
+		{token.LBRACE, "{"},
+		{token.STRING, "foo"},
+		{token.COLON, ":"},
+		{token.STRING, "bar"},
+		{token.RBRACE, "}"},
+		{token.SQUAD, "squad"},
+		{token.IDENT, "Opener"},
+		{token.EXTENDS, "extends"},
+		{token.IDENT, "Batter"},
+		{token.LBRACE, "{"},
+		{token.RBRACE, "}"},
+		{token.IDENT, "self"},
+		{token.DOT, "."},
+		{token.IDENT, "runs"},
+		{token.ASSIGN, "="},
+		{token.INT, "4"},
+		{token.SEMICOLON, ";"},
+		{token.UMPIRE, "umpire"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.LBRACKET, "["},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "rest"},
+		{token.RBRACKET, "]"},
+		{token.ARROW, "->"},
+		{token.IDENT, "a"},
+		{token.SEMICOLON, ";"},
+		{token.RBRACE, "}"},
+		{token.IDENT, "a"},
+		{token.DOT, "."},
+		{token.DOT, "."},
+		{token.IDENT, "b"},
 		{token.EOF, "MATCH_ENDED"},
 	}
 
@@ -130,3 +171,81 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestTokenPositions(t *testing.T) {
+	input := "player x = 5;\n  appeal (x > 1) {\n\t\"hi\"\n}"
+
+	tests := []struct {
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{"player", 1, 1},
+		{"x", 1, 8},
+		{"=", 1, 10},
+		{"5", 1, 12},
+		{";", 1, 13},
+		{"appeal", 2, 3},
+		{"(", 2, 10},
+		{"x", 2, 11},
+		{">", 2, 13},
+		{"1", 2, 15},
+		{")", 2, 16},
+		{"{", 2, 18},
+		{"hi", 3, 2},
+		{"}", 4, 1},
+		{"MATCH_ENDED", 4, 2},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - position of %q wrong. expected=%d:%d, got=%d:%d",
+				i, tok.Literal, tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	input := `// opening over
+player x = 5; // trailing
+// two in a row
+// back to back
+x / 2`
+
+	expectedTypes := []token.TokenType{
+		token.PLAYER, token.IDENT, token.ASSIGN, token.INT, token.SEMICOLON,
+		token.IDENT, token.SLASH, token.INT, token.EOF,
+	}
+
+	l := New(input)
+	for i, expected := range expectedTypes {
+		tok := l.NextToken()
+		if tok.Type != expected {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, expected, tok.Type)
+		}
+	}
+
+	expectedComments := []token.Token{
+		{Type: token.COMMENT, Literal: "// opening over", Line: 1, Column: 1},
+		{Type: token.COMMENT, Literal: "// trailing", Line: 2, Column: 15},
+		{Type: token.COMMENT, Literal: "// two in a row", Line: 3, Column: 1},
+		{Type: token.COMMENT, Literal: "// back to back", Line: 4, Column: 1},
+	}
+
+	comments := l.Comments()
+	if len(comments) != len(expectedComments) {
+		t.Fatalf("wrong number of comments. expected=%d, got=%d", len(expectedComments), len(comments))
+	}
+	for i, expected := range expectedComments {
+		if comments[i] != expected {
+			t.Errorf("comments[%d] wrong. expected=%+v, got=%+v", i, expected, comments[i])
+		}
+	}
+}
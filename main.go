@@ -2,17 +2,61 @@ package main
 
 import (
 	"CricLang/repl"
+	"flag"
 	"fmt"
 	"os"
 	"os/user"
+	"strings"
 )
 
 func main() {
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		os.Exit(runCommand(os.Args[1], os.Args[2:]))
+	}
+	os.Exit(replCommand(os.Args[1:]))
+}
+
+func replCommand(args []string) int {
+	flags := flag.NewFlagSet("criclang", flag.ContinueOnError)
+	seed := seedFlag(flags)
+	allowed := allowDirFlag(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: criclang [--seed n] [--allow-dir dir] | criclang [parse|fmt|run] ...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return 2
+	}
+
 	user, err := user.Current()
 	if err != nil {
 		panic(err)
 	}
 
+	runtime := newRuntime(nil)
+	applySeed(runtime, flags, *seed)
+	runtime.FileRoots = *allowed
+
 	fmt.Printf("Welcome %s to CricLang: A fun programming language for cricket enthusiasts!\n", user.Username)
-	repl.Start(os.Stdin, os.Stdout)
+	repl.Start(os.Stdin, os.Stdout, runtime)
+	return 0
+}
+
+func runCommand(name string, args []string) int {
+	switch name {
+	case "parse":
+		return parseCommand(args)
+	case "fmt":
+		return fmtCommand(args)
+	case "run":
+		return runFileCommand(args)
+	default:
+		fmt.Fprintf(os.Stderr, "criclang: unknown command %q\n", name)
+		fmt.Fprintln(os.Stderr, "usage: criclang [parse|fmt|run] ...")
+		return 2
+	}
 }
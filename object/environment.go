@@ -1,19 +1,32 @@
 package object
 
+import "sort"
+
 func NewEnclosedEnvironment(outer *Environment) *Environment {
 	env := NewEnvironment()
 	env.outer = outer
+	env.runtime = outer.runtime
+	env.dir = outer.dir
 	return env
 }
 
 func NewEnvironment() *Environment {
+	return NewRuntimeEnvironment(NewRuntime(), "")
+}
+
+// NewRuntimeEnvironment returns a top-level environment sharing runtime with
+// others. Relative imports made from it are resolved against dir, or the
+// working directory when dir is empty.
+func NewRuntimeEnvironment(runtime *Runtime, dir string) *Environment {
 	s := make(map[string]Object)
-	return &Environment{store: s, outer: nil}
+	return &Environment{store: s, outer: nil, runtime: runtime, dir: dir}
 }
 
 type Environment struct {
-	store map[string]Object
-	outer *Environment
+	store   map[string]Object
+	outer   *Environment
+	runtime *Runtime
+	dir     string
 }
 
 func (e *Environment) Get(name string) (Object, bool) {
@@ -28,3 +41,17 @@ func (e *Environment) Set(name string, val Object) Object {
 	e.store[name] = val
 	return val
 }
+
+func (e *Environment) Runtime() *Runtime { return e.runtime }
+
+func (e *Environment) Dir() string { return e.dir }
+
+// Names returns the sorted names bound directly in e, ignoring outer scopes.
+func (e *Environment) Names() []string {
+	names := make([]string, 0, len(e.store))
+	for name := range e.store {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,415 @@
+package object
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Dismissals lists the ways a batter can be out. Only a run out can fall to
+// the non-striker or come with runs completed.
+var Dismissals = map[string]bool{
+	"bowled":     true,
+	"caught":     true,
+	"lbw":        true,
+	"stumped":    true,
+	"hit wicket": true,
+	"run out":    true,
+}
+
+type Batter struct {
+	Name      string
+	Runs      int64
+	Balls     int64
+	Fours     int64
+	Sixes     int64
+	Batted    bool
+	Dismissal string // empty while not out
+}
+
+type Extras struct {
+	Byes    int64
+	LegByes int64
+	Wides   int64
+	NoBalls int64
+}
+
+func (e Extras) Total() int64 { return e.Byes + e.LegByes + e.Wides + e.NoBalls }
+
+type FallOfWicket struct {
+	Wicket int64
+	Score  int64
+	Batter string
+	Balls  int64 // legal deliveries bowled when the wicket fell
+}
+
+type Partnership struct {
+	Batters [2]string
+	Runs    int64
+	Balls   int64
+	Ended   bool
+}
+
+// Innings records one team's innings delivery by delivery. Striker and
+// NonStriker index into Batters; the next batter in is the first one who
+// hasn't batted yet.
+type Innings struct {
+	Team         string
+	Batters      []*Batter
+	MaxOvers     int64 // zero for no limit
+	Striker      int
+	NonStriker   int
+	Total        int64
+	Wickets      int64
+	Balls        int64
+	Extras       Extras
+	Fall         []FallOfWicket
+	Partnerships []*Partnership
+}
+
+func NewInnings(team string, battingOrder []string, maxOvers int64) (*Innings, error) {
+	if len(battingOrder) < 2 {
+		return nil, fmt.Errorf("an innings needs at least 2 batters, got %d", len(battingOrder))
+	}
+
+	seen := map[string]bool{}
+	inn := &Innings{Team: team, MaxOvers: maxOvers, Striker: 0, NonStriker: 1}
+	for _, name := range battingOrder {
+		if seen[name] {
+			return nil, fmt.Errorf("%s appears twice in the batting order", name)
+		}
+		seen[name] = true
+		inn.Batters = append(inn.Batters, &Batter{Name: name})
+	}
+
+	inn.Batters[0].Batted = true
+	inn.Batters[1].Batted = true
+	inn.Partnerships = []*Partnership{{Batters: [2]string{battingOrder[0], battingOrder[1]}}}
+	return inn, nil
+}
+
+func (inn *Innings) Type() ObjectType { return INNINGS_OBJ }
+
+// Over reports whether the innings can take no more deliveries.
+func (inn *Innings) Over() bool {
+	return inn.Wickets == int64(len(inn.Batters)-1) ||
+		(inn.MaxOvers > 0 && inn.Balls >= inn.MaxOvers*BallsPerOver)
+}
+
+func (inn *Innings) Overs() string { return FormatOvers(inn.Balls) }
+
+// Runs records a legal delivery the striker scored runs off.
+func (inn *Innings) Runs(runs int64) error {
+	if err := inn.checkDelivery(runs); err != nil {
+		return err
+	}
+	batter := inn.Batters[inn.Striker]
+	batter.Runs += runs
+	batter.Balls++
+	switch runs {
+	case 4:
+		batter.Fours++
+	case 6:
+		batter.Sixes++
+	}
+	inn.score(runs, true)
+	inn.run(runs)
+	inn.legalBall()
+	return nil
+}
+
+// Wide records a wide plus any runs taken off it. It isn't a legal
+// delivery and the striker doesn't face it.
+func (inn *Innings) Wide(runs int64) error {
+	if err := inn.checkDelivery(runs); err != nil {
+		return err
+	}
+	inn.Extras.Wides += 1 + runs
+	inn.score(1+runs, false)
+	inn.run(runs)
+	return nil
+}
+
+// NoBall records a no-ball plus the runs the striker hit off it. It isn't
+// a legal delivery, but the striker did face it.
+func (inn *Innings) NoBall(runs int64) error {
+	if err := inn.checkDelivery(runs); err != nil {
+		return err
+	}
+	batter := inn.Batters[inn.Striker]
+	batter.Runs += runs
+	batter.Balls++
+	switch runs {
+	case 4:
+		batter.Fours++
+	case 6:
+		batter.Sixes++
+	}
+	inn.Extras.NoBalls++
+	inn.score(1+runs, false)
+	inn.run(runs)
+	return nil
+}
+
+func (inn *Innings) Bye(runs int64) error {
+	if err := inn.checkDelivery(runs); err != nil {
+		return err
+	}
+	inn.Extras.Byes += runs
+	inn.Batters[inn.Striker].Balls++
+	inn.score(runs, true)
+	inn.run(runs)
+	inn.legalBall()
+	return nil
+}
+
+func (inn *Innings) LegBye(runs int64) error {
+	if err := inn.checkDelivery(runs); err != nil {
+		return err
+	}
+	inn.Extras.LegByes += runs
+	inn.Batters[inn.Striker].Balls++
+	inn.score(runs, true)
+	inn.run(runs)
+	inn.legalBall()
+	return nil
+}
+
+// Wicket records a legal delivery that took a wicket. For a run out, runs
+// completed before it count to the striker, and nonStriker says which end
+// the batter was run out at.
+func (inn *Innings) Wicket(kind string, runs int64, nonStriker bool) error {
+	if err := inn.checkDelivery(runs); err != nil {
+		return err
+	}
+	if !Dismissals[kind] {
+		return fmt.Errorf("unknown dismissal %q", kind)
+	}
+	if kind != "run out" && (runs != 0 || nonStriker) {
+		return fmt.Errorf("only a run out can complete runs or dismiss the non-striker")
+	}
+
+	striker := inn.Batters[inn.Striker]
+	striker.Runs += runs
+	striker.Balls++
+	inn.score(runs, true)
+	inn.run(runs)
+
+	out := inn.Striker
+	if nonStriker {
+		out = inn.NonStriker
+	}
+	inn.dismiss(out, kind)
+	inn.legalBall()
+	return nil
+}
+
+func (inn *Innings) checkDelivery(runs int64) error {
+	if inn.Over() {
+		return fmt.Errorf("the %s innings is over", inn.Team)
+	}
+	if runs < 0 {
+		return fmt.Errorf("runs can't be negative, got %d", runs)
+	}
+	return nil
+}
+
+func (inn *Innings) score(runs int64, legal bool) {
+	inn.Total += runs
+	partnership := inn.Partnerships[len(inn.Partnerships)-1]
+	partnership.Runs += runs
+	if legal {
+		partnership.Balls++
+	}
+}
+
+// run swaps ends when the batters ran an odd number of runs.
+func (inn *Innings) run(runs int64) {
+	if runs%2 == 1 {
+		inn.Striker, inn.NonStriker = inn.NonStriker, inn.Striker
+	}
+}
+
+// legalBall counts a legal delivery and changes ends at the end of an over.
+func (inn *Innings) legalBall() {
+	inn.Balls++
+	if inn.Balls%BallsPerOver == 0 {
+		inn.Striker, inn.NonStriker = inn.NonStriker, inn.Striker
+	}
+}
+
+func (inn *Innings) dismiss(out int, kind string) {
+	batter := inn.Batters[out]
+	batter.Dismissal = kind
+	inn.Wickets++
+	// legalBall hasn't run yet for the wicket ball
+	inn.Fall = append(inn.Fall, FallOfWicket{Wicket: inn.Wickets, Score: inn.Total, Batter: batter.Name, Balls: inn.Balls + 1})
+	inn.Partnerships[len(inn.Partnerships)-1].Ended = true
+
+	next := -1
+	for i, b := range inn.Batters {
+		if !b.Batted {
+			next = i
+			break
+		}
+	}
+	if next < 0 {
+		return
+	}
+
+	inn.Batters[next].Batted = true
+	if out == inn.Striker {
+		inn.Striker = next
+	} else {
+		inn.NonStriker = next
+	}
+	inn.Partnerships = append(inn.Partnerships, &Partnership{
+		Batters: [2]string{inn.Batters[inn.Striker].Name, inn.Batters[inn.NonStriker].Name},
+	})
+}
+
+// Attribute exposes the innings to programs as inn["name"].
+func (inn *Innings) Attribute(name string) (Object, bool) {
+	switch name {
+	case "team":
+		return &String{Value: inn.Team}, true
+	case "runs":
+		return &Integer{Value: inn.Total}, true
+	case "wickets":
+		return &Integer{Value: inn.Wickets}, true
+	case "balls":
+		return &Integer{Value: inn.Balls}, true
+	case "overs":
+		return &String{Value: inn.Overs()}, true
+	case "striker":
+		return &String{Value: inn.Batters[inn.Striker].Name}, true
+	case "nonStriker":
+		return &String{Value: inn.Batters[inn.NonStriker].Name}, true
+	case "extras":
+		extras := NewHash()
+		extras.Set("byes", &Integer{Value: inn.Extras.Byes})
+		extras.Set("legByes", &Integer{Value: inn.Extras.LegByes})
+		extras.Set("wides", &Integer{Value: inn.Extras.Wides})
+		extras.Set("noBalls", &Integer{Value: inn.Extras.NoBalls})
+		extras.Set("total", &Integer{Value: inn.Extras.Total()})
+		return extras, true
+	case "batters":
+		batters := []Object{}
+		for _, b := range inn.Batters {
+			if !b.Batted {
+				continue
+			}
+			card := NewHash()
+			card.Set("name", &String{Value: b.Name})
+			card.Set("runs", &Integer{Value: b.Runs})
+			card.Set("balls", &Integer{Value: b.Balls})
+			card.Set("fours", &Integer{Value: b.Fours})
+			card.Set("sixes", &Integer{Value: b.Sixes})
+			card.Set("dismissal", &String{Value: dismissalText(b)})
+			batters = append(batters, card)
+		}
+		return &Array{Elements: batters}, true
+	case "fallOfWickets":
+		fall := []Object{}
+		for _, f := range inn.Fall {
+			wicket := NewHash()
+			wicket.Set("wicket", &Integer{Value: f.Wicket})
+			wicket.Set("score", &Integer{Value: f.Score})
+			wicket.Set("batter", &String{Value: f.Batter})
+			wicket.Set("overs", &String{Value: FormatOvers(f.Balls)})
+			fall = append(fall, wicket)
+		}
+		return &Array{Elements: fall}, true
+	case "partnerships":
+		partnerships := []Object{}
+		for _, p := range inn.Partnerships {
+			partnership := NewHash()
+			partnership.Set("batters", &Array{Elements: []Object{&String{Value: p.Batters[0]}, &String{Value: p.Batters[1]}}})
+			partnership.Set("runs", &Integer{Value: p.Runs})
+			partnership.Set("balls", &Integer{Value: p.Balls})
+			partnerships = append(partnerships, partnership)
+		}
+		return &Array{Elements: partnerships}, true
+	}
+	return nil, false
+}
+
+func dismissalText(b *Batter) string {
+	if b.Dismissal == "" {
+		return "not out"
+	}
+	return b.Dismissal
+}
+
+// Inspect renders the batting scorecard.
+func (inn *Innings) Inspect() string {
+	var out bytes.Buffer
+
+	fmt.Fprintf(&out, "%s innings\n", inn.Team)
+	fmt.Fprintf(&out, "%-20s %-12s %5s  %4s %4s %4s %8s\n", "Batter", "", "R", "B", "4s", "6s", "SR")
+
+	didNotBat := []string{}
+	for _, b := range inn.Batters {
+		if !b.Batted {
+			didNotBat = append(didNotBat, b.Name)
+			continue
+		}
+		strikeRate := 0.0
+		if b.Balls > 0 {
+			strikeRate = float64(b.Runs) * 100 / float64(b.Balls)
+		}
+		// a not out batter's runs are starred; the star hangs past the column
+		notOut := " "
+		if b.Dismissal == "" {
+			notOut = "*"
+		}
+		fmt.Fprintf(&out, "%-20s %-12s %5d%s %4d %4d %4d %8.2f\n",
+			b.Name, dismissalText(b), b.Runs, notOut, b.Balls, b.Fours, b.Sixes, strikeRate)
+	}
+
+	e := inn.Extras
+	fmt.Fprintf(&out, "%-20s %-24s %5d\n", "Extras",
+		fmt.Sprintf("(b %d, lb %d, w %d, nb %d)", e.Byes, e.LegByes, e.Wides, e.NoBalls), e.Total())
+	fmt.Fprintf(&out, "%-20s %-24s %5d\n", "Total",
+		fmt.Sprintf("(%d wkts, %s ov)", inn.Wickets, inn.Overs()), inn.Total)
+
+	if len(didNotBat) > 0 {
+		fmt.Fprintf(&out, "Did not bat: %s\n", strings.Join(didNotBat, ", "))
+	}
+
+	if len(inn.Fall) > 0 {
+		fall := []string{}
+		for _, f := range inn.Fall {
+			fall = append(fall, fmt.Sprintf("%d-%d (%s, %s ov)", f.Wicket, f.Score, f.Batter, FormatOvers(f.Balls)))
+		}
+		fmt.Fprintf(&out, "Fall of wickets: %s\n", strings.Join(fall, ", "))
+	}
+
+	partnerships := []string{}
+	for i, p := range inn.Partnerships {
+		notOut := "*"
+		if p.Ended {
+			notOut = ""
+		}
+		partnerships = append(partnerships, fmt.Sprintf("%s wkt %d%s (%d) %s & %s",
+			ordinal(i+1), p.Runs, notOut, p.Balls, p.Batters[0], p.Batters[1]))
+	}
+	fmt.Fprintf(&out, "Partnerships: %s", strings.Join(partnerships, ", "))
+
+	return out.String()
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
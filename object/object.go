@@ -4,6 +4,8 @@ import (
 	"CricLang/ast"
 	"bytes"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 )
 
@@ -11,6 +13,7 @@ type ObjectType string
 
 const (
 	INTEGER_OBJ                     = "INTEGER"
+	FLOAT_OBJ                       = "FLOAT"
 	BOOLEAN_OBJ                     = "BOOLEAN"
 	DEAD_BALL_NULL_OBJ              = "DEAD_BALL"
 	SIGNALDECISION_RETURN_VALUE_OBJ = "SIGNALDECISION"
@@ -19,8 +22,26 @@ const (
 	STRING_OBJ                      = "STRING"
 	BUILTIN_OBJ                     = "BUILTIN"
 	ARRAY_OBJ                       = "ARRAY"
+	HASH_OBJ                        = "HASH"
+	QUOTE_OBJ                       = "QUOTE"
+	MACRO_OBJ                       = "MACRO"
+	MODULE_OBJ                      = "MODULE"
+	INNINGS_OBJ                     = "INNINGS"
+	SQUAD_OBJ                       = "SQUAD"
 )
 
+// IsBuiltinType reports whether name is the type of a builtin object. A
+// squad can't take such a name, since its instances are typed by it.
+func IsBuiltinType(name string) bool {
+	switch ObjectType(name) {
+	case INTEGER_OBJ, FLOAT_OBJ, BOOLEAN_OBJ, DEAD_BALL_NULL_OBJ, SIGNALDECISION_RETURN_VALUE_OBJ,
+		MISFIELD_ERROR_OBJECT, FIELD_FUNCTION_OBJECT, STRING_OBJ, BUILTIN_OBJ, ARRAY_OBJ, HASH_OBJ,
+		QUOTE_OBJ, MACRO_OBJ, MODULE_OBJ, INNINGS_OBJ, SQUAD_OBJ:
+		return true
+	}
+	return false
+}
+
 type Object interface {
 	Type() ObjectType
 	Inspect() string
@@ -33,6 +54,22 @@ type Integer struct {
 func (i *Integer) Type() ObjectType { return INTEGER_OBJ }
 func (i *Integer) Inspect() string  { return fmt.Sprintf("%d", i.Value) }
 
+type Float struct {
+	Value float64
+}
+
+func (f *Float) Type() ObjectType { return FLOAT_OBJ }
+
+// Inspect always shows a decimal point for whole values so 2.0 can't be
+// mistaken for the integer 2.
+func (f *Float) Inspect() string {
+	s := strconv.FormatFloat(f.Value, 'f', -1, 64)
+	if !math.IsInf(f.Value, 0) && !math.IsNaN(f.Value) && !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
 type Boolean struct {
 	Value bool
 }
@@ -60,7 +97,7 @@ func (m *Misfield) Type() ObjectType { return MISFIELD_ERROR_OBJECT }
 func (m *Misfield) Inspect() string  { return "MISFIELD: " + m.Message }
 
 type Field struct {
-	Parameters []*ast.Identifier
+	Parameters []ast.Expression
 	Body       *ast.BlockStatement
 	Env        *Environment
 }
@@ -92,7 +129,15 @@ type String struct {
 func (s *String) Type() ObjectType { return STRING_OBJ }
 func (s *String) Inspect() string  { return s.Value }
 
-type BuiltinField func(args ...Object) Object
+// CallContext is handed to every builtin call. Env is the environment of
+// the caller and Apply calls a field or builtin the way a call expression
+// would, so builtins can take callbacks.
+type CallContext struct {
+	Env   *Environment
+	Apply func(fn Object, args ...Object) Object
+}
+
+type BuiltinField func(ctx *CallContext, args ...Object) Object
 
 type Builtin struct {
 	Fn BuiltinField
@@ -120,3 +165,93 @@ func (a *Array) Inspect() string {
 
 	return out.String()
 }
+
+// Hash maps strings to values. Keys remember the order they were first set
+// in, which is the order Inspect and toJSON use.
+type Hash struct {
+	Keys  []string
+	Pairs map[string]Object
+}
+
+func NewHash() *Hash {
+	return &Hash{Keys: []string{}, Pairs: make(map[string]Object)}
+}
+
+func (h *Hash) Get(key string) (Object, bool) {
+	obj, ok := h.Pairs[key]
+	return obj, ok
+}
+
+func (h *Hash) Set(key string, val Object) {
+	if _, ok := h.Pairs[key]; !ok {
+		h.Keys = append(h.Keys, key)
+	}
+	h.Pairs[key] = val
+}
+
+func (h *Hash) Type() ObjectType { return HASH_OBJ }
+func (h *Hash) Inspect() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+	for _, key := range h.Keys {
+		pairs = append(pairs, key+": "+h.Pairs[key].Inspect())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+type Quote struct {
+	Node ast.Node
+}
+
+func (q *Quote) Type() ObjectType { return QUOTE_OBJ }
+func (q *Quote) Inspect() string {
+	return "QUOTE(" + q.Node.String() + ")"
+}
+
+type Macro struct {
+	Parameters []*ast.Identifier
+	Body       *ast.BlockStatement
+	Env        *Environment
+}
+
+func (m *Macro) Type() ObjectType { return MACRO_OBJ }
+func (m *Macro) Inspect() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range m.Parameters {
+		params = append(params, p.String())
+	}
+
+	out.WriteString("macro")
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") {\n")
+	out.WriteString(m.Body.String())
+	out.WriteString("\n}")
+
+	return out.String()
+}
+
+// Module is an imported file. Its top-level players are its exports.
+type Module struct {
+	Path string
+	Env  *Environment
+}
+
+func (m *Module) Type() ObjectType { return MODULE_OBJ }
+func (m *Module) Inspect() string {
+	return "module(" + m.Path + ") {" + strings.Join(m.Env.Names(), ", ") + "}"
+}
+
+// Export looks up a top-level player of the module.
+func (m *Module) Export(name string) (Object, bool) {
+	obj, ok := m.Env.store[name]
+	return obj, ok
+}
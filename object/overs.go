@@ -0,0 +1,39 @@
+package object
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const BallsPerOver = 6
+
+// FormatOvers writes a ball count in overs notation: 118 balls is "19.4",
+// nineteen overs and four balls.
+func FormatOvers(balls int64) string {
+	sign := ""
+	if balls < 0 {
+		sign, balls = "-", -balls
+	}
+	return fmt.Sprintf("%s%d.%d", sign, balls/BallsPerOver, balls%BallsPerOver)
+}
+
+// ParseOvers reads overs notation back into a ball count. "19.4" is 118
+// balls; "20" and "20.0" are 120. The part after the point counts balls, so
+// it must be below six.
+func ParseOvers(overs string) (int64, error) {
+	whole, part, found := strings.Cut(strings.TrimSpace(overs), ".")
+	completed, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || completed < 0 || strings.HasPrefix(whole, "+") {
+		return 0, fmt.Errorf("invalid overs %q", overs)
+	}
+	if !found {
+		return completed * BallsPerOver, nil
+	}
+
+	balls, err := strconv.ParseInt(part, 10, 64)
+	if err != nil || len(part) != 1 || balls >= BallsPerOver {
+		return 0, fmt.Errorf("invalid overs %q: the part after the point counts balls, 0 to %d", overs, BallsPerOver-1)
+	}
+	return completed*BallsPerOver + balls, nil
+}
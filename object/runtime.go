@@ -0,0 +1,48 @@
+package object
+
+import (
+	"io"
+	"math/rand"
+	"os"
+	"time"
+)
+
+// Runtime holds the state shared by every environment of one interpreter:
+// where imports are searched for and which modules are already loaded.
+type Runtime struct {
+	// IncludePath lists the directories searched for an import that isn't
+	// found next to the file importing it.
+	IncludePath []string
+
+	// Modules caches every successfully loaded module by its resolved path.
+	Modules map[string]*Module
+
+	// Loading is the stack of modules currently being evaluated, used to
+	// detect import cycles.
+	Loading []string
+
+	// Out is where print and friends write.
+	Out io.Writer
+
+	// FileRoots are the only directories the file builtins may touch. File
+	// access is disabled while it is empty.
+	FileRoots []string
+
+	// Rand is the source of every random choice the program makes. Seed it
+	// to make a run reproducible.
+	Rand *rand.Rand
+}
+
+func NewRuntime() *Runtime {
+	return &Runtime{
+		Modules: make(map[string]*Module),
+		Out:     os.Stdout,
+		Rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Seed resets the runtime's random source so the same seed replays the same
+// sequence of random choices.
+func (r *Runtime) Seed(seed int64) {
+	r.Rand = rand.New(rand.NewSource(seed))
+}
@@ -0,0 +1,63 @@
+package object
+
+import "strings"
+
+// Squad is a type declared with `squad`. Calling it builds an Instance
+// with the parent's fields followed by the squad's own.
+type Squad struct {
+	Name    string
+	Parent  *Squad
+	Fields  []string          // declared by this squad, not inherited
+	Methods map[string]*Field // declared by this squad, not inherited
+}
+
+func (s *Squad) Type() ObjectType { return SQUAD_OBJ }
+func (s *Squad) Inspect() string {
+	out := "squad " + s.Name + "(" + strings.Join(s.Fields, ", ") + ")"
+	if s.Parent != nil {
+		out += " extends " + s.Parent.Name
+	}
+	return out
+}
+
+// AllFields lists the fields of an instance in constructor order,
+// inherited ones first.
+func (s *Squad) AllFields() []string {
+	if s.Parent == nil {
+		return s.Fields
+	}
+	return append(append([]string{}, s.Parent.AllFields()...), s.Fields...)
+}
+
+// Method looks name up on s and then on each of its ancestors in turn, so
+// a squad can override what it inherits.
+func (s *Squad) Method(name string) (*Field, bool) {
+	for squad := s; squad != nil; squad = squad.Parent {
+		if method, ok := squad.Methods[name]; ok {
+			return method, true
+		}
+	}
+	return nil, false
+}
+
+// Instance is a value built by calling a squad. Its type is the squad's
+// name, so every squad is a type of its own.
+type Instance struct {
+	Squad  *Squad
+	Fields *Hash
+
+	inspecting bool // set while Inspect is printing the fields
+}
+
+func (i *Instance) Type() ObjectType { return ObjectType(i.Squad.Name) }
+
+// Inspect prints an instance that holds itself, directly or through an
+// array or hash, as Name{...} the second time round.
+func (i *Instance) Inspect() string {
+	if i.inspecting {
+		return i.Squad.Name + "{...}"
+	}
+	i.inspecting = true
+	defer func() { i.inspecting = false }()
+	return i.Squad.Name + i.Fields.Inspect()
+}
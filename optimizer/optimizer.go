@@ -0,0 +1,328 @@
+package optimizer
+
+import (
+	"CricLang/ast"
+	"CricLang/token"
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+type RemovalKind string
+
+const (
+	CONSTANT_FOLDED     = "CONSTANT_FOLDED"
+	BRANCH_COLLAPSED    = "BRANCH_COLLAPSED"
+	UNREACHABLE_DROPPED = "UNREACHABLE_DROPPED"
+)
+
+// Removal records a single piece of the program the optimizer took out,
+// along with what (if anything) it was replaced by.
+type Removal struct {
+	Kind        RemovalKind
+	Original    string
+	Replacement string
+}
+
+func (r Removal) String() string {
+	if r.Replacement == "" {
+		return fmt.Sprintf("%s: %s", r.Kind, r.Original)
+	}
+	return fmt.Sprintf("%s: %s => %s", r.Kind, r.Original, r.Replacement)
+}
+
+type Report struct {
+	Removals []Removal
+}
+
+func (r *Report) String() string {
+	var out bytes.Buffer
+
+	for _, rm := range r.Removals {
+		out.WriteString(rm.String())
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+func (r *Report) record(kind RemovalKind, original ast.Node, replacement ast.Node) {
+	rm := Removal{Kind: kind, Original: original.String()}
+	if replacement != nil {
+		rm.Replacement = replacement.String()
+	}
+	r.Removals = append(r.Removals, rm)
+}
+
+// Optimize rewrites program in place so that it evaluates to the same
+// result with less work, and reports everything it removed on the way.
+func Optimize(program *ast.Program) *Report {
+	o := &optimizer{report: &Report{}}
+	program.Statements = o.optimizeStatements(program.Statements)
+	return o.report
+}
+
+type optimizer struct {
+	report *Report
+}
+
+func (o *optimizer) optimizeStatements(stmts []ast.Statement) []ast.Statement {
+	result := []ast.Statement{}
+
+	for i, stmt := range stmts {
+		stmt = o.optimizeStatement(stmt)
+
+		if es, ok := stmt.(*ast.ExpressionStatement); ok {
+			if appeal, ok := es.Expression.(*ast.AppealIfExpression); ok {
+				if taken, constant := takenBranch(appeal); constant {
+					isLast := i == len(stmts)-1
+					switch {
+					case taken != nil && len(taken.Statements) > 0:
+						o.report.record(BRANCH_COLLAPSED, appeal, taken)
+						result = append(result, taken.Statements...)
+						continue
+					case !isLast:
+						o.report.record(BRANCH_COLLAPSED, appeal, nil)
+						continue
+					}
+				}
+			}
+		}
+
+		result = append(result, stmt)
+	}
+
+	for i, stmt := range result {
+		if _, ok := stmt.(*ast.SignalDecisionStatement); ok {
+			for _, dead := range result[i+1:] {
+				o.report.record(UNREACHABLE_DROPPED, dead, nil)
+			}
+			return result[:i+1]
+		}
+	}
+	return result
+}
+
+func (o *optimizer) optimizeStatement(stmt ast.Statement) ast.Statement {
+	switch stmt := stmt.(type) {
+	case *ast.ExpressionStatement:
+		stmt.Expression = o.optimizeExpression(stmt.Expression)
+	case *ast.PlayerStatement:
+		stmt.Value = o.optimizeExpression(stmt.Value)
+	case *ast.SignalDecisionStatement:
+		stmt.SignalDecisionValue = o.optimizeExpression(stmt.SignalDecisionValue)
+	case *ast.BlockStatement:
+		o.optimizeBlock(stmt)
+	case *ast.SquadStatement:
+		for _, method := range stmt.Body.Statements {
+			o.optimizeStatement(method)
+		}
+	}
+	return stmt
+}
+
+func (o *optimizer) optimizeBlock(block *ast.BlockStatement) {
+	if block != nil {
+		block.Statements = o.optimizeStatements(block.Statements)
+	}
+}
+
+func (o *optimizer) optimizeExpression(exp ast.Expression) ast.Expression {
+	switch exp := exp.(type) {
+	case *ast.PrefixExpression:
+		exp.Right = o.optimizeExpression(exp.Right)
+		if folded := foldPrefix(exp); folded != nil {
+			o.report.record(CONSTANT_FOLDED, exp, folded)
+			return folded
+		}
+	case *ast.InfixExpression:
+		exp.Left = o.optimizeExpression(exp.Left)
+		exp.Right = o.optimizeExpression(exp.Right)
+		if folded := foldInfix(exp); folded != nil {
+			o.report.record(CONSTANT_FOLDED, exp, folded)
+			return folded
+		}
+	case *ast.AppealIfExpression:
+		return o.optimizeAppeal(exp)
+	case *ast.FieldLiteral:
+		o.optimizeBlock(exp.Body)
+	case *ast.CallExpression:
+		// a quoted expression is a value in its own right; folding it would
+		// change what the quote holds
+		if exp.Function.TokenLiteral() == "quote" {
+			return exp
+		}
+		exp.Function = o.optimizeExpression(exp.Function)
+		for i, arg := range exp.Arguments {
+			exp.Arguments[i] = o.optimizeExpression(arg)
+		}
+	case *ast.ArrayLiteral:
+		for i, el := range exp.Elements {
+			exp.Elements[i] = o.optimizeExpression(el)
+		}
+	case *ast.HashLiteral:
+		for i, pair := range exp.Pairs {
+			exp.Pairs[i].Key = o.optimizeExpression(pair.Key)
+			exp.Pairs[i].Value = o.optimizeExpression(pair.Value)
+		}
+	case *ast.IndexExpression:
+		exp.Left = o.optimizeExpression(exp.Left)
+		exp.Index = o.optimizeExpression(exp.Index)
+	case *ast.DotExpression:
+		exp.Left = o.optimizeExpression(exp.Left)
+	case *ast.AssignExpression:
+		exp.Target = o.optimizeExpression(exp.Target)
+		exp.Value = o.optimizeExpression(exp.Value)
+	case *ast.UmpireExpression:
+		// patterns are matched, not evaluated, so only the subject, guards
+		// and bodies are touched
+		exp.Subject = o.optimizeExpression(exp.Subject)
+		for i, arm := range exp.Arms {
+			if arm.Guard != nil {
+				exp.Arms[i].Guard = o.optimizeExpression(arm.Guard)
+			}
+			exp.Arms[i].Body = o.optimizeExpression(arm.Body)
+		}
+	}
+	return exp
+}
+
+// optimizeAppeal collapses an appeal whose condition is a literal. In
+// expression position we can only replace it outright when the taken branch
+// is a single expression; otherwise the dead branch is emptied and the
+// statement-level pass gets a chance to splice the taken branch in.
+func (o *optimizer) optimizeAppeal(appeal *ast.AppealIfExpression) ast.Expression {
+	appeal.Condition = o.optimizeExpression(appeal.Condition)
+	o.optimizeBlock(appeal.Consequence)
+	o.optimizeBlock(appeal.Alternative)
+
+	taken, constant := takenBranch(appeal)
+	if !constant {
+		return appeal
+	}
+
+	if taken != nil && len(taken.Statements) == 1 {
+		if es, ok := taken.Statements[0].(*ast.ExpressionStatement); ok && es.Expression != nil {
+			o.report.record(BRANCH_COLLAPSED, appeal, es.Expression)
+			return es.Expression
+		}
+	}
+
+	if taken == appeal.Consequence && appeal.Alternative != nil {
+		o.report.record(BRANCH_COLLAPSED, appeal.Alternative, nil)
+		appeal.Alternative = nil
+	} else if taken != appeal.Consequence && len(appeal.Consequence.Statements) > 0 {
+		o.report.record(BRANCH_COLLAPSED, appeal.Consequence, nil)
+		appeal.Consequence = &ast.BlockStatement{
+			Token:    appeal.Consequence.Token,
+			EndToken: appeal.Consequence.EndToken,
+		}
+	}
+	return appeal
+}
+
+// takenBranch reports which block an appeal with a literal condition will
+// always run. The block is nil when the condition fails and there is no
+// appealrejected branch.
+func takenBranch(appeal *ast.AppealIfExpression) (*ast.BlockStatement, bool) {
+	truthy, ok := literalTruthiness(appeal.Condition)
+	if !ok {
+		return nil, false
+	}
+	if truthy {
+		return appeal.Consequence, true
+	}
+	return appeal.Alternative, true
+}
+
+func literalTruthiness(exp ast.Expression) (bool, bool) {
+	switch exp := exp.(type) {
+	case *ast.Boolean:
+		return exp.Value, true
+	case *ast.IntegerLiteral, *ast.StringLiteral:
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+func foldPrefix(pe *ast.PrefixExpression) ast.Expression {
+	switch pe.Operator {
+	case "!":
+		switch right := pe.Right.(type) {
+		case *ast.Boolean:
+			return newBoolean(!right.Value)
+		case *ast.IntegerLiteral, *ast.StringLiteral:
+			return newBoolean(false)
+		}
+	case "-":
+		if right, ok := pe.Right.(*ast.IntegerLiteral); ok {
+			return newInteger(-right.Value)
+		}
+	}
+	return nil
+}
+
+func foldInfix(ie *ast.InfixExpression) ast.Expression {
+	switch left := ie.Left.(type) {
+	case *ast.IntegerLiteral:
+		if right, ok := ie.Right.(*ast.IntegerLiteral); ok {
+			return foldIntegerInfix(ie.Operator, left.Value, right.Value)
+		}
+	case *ast.StringLiteral:
+		if right, ok := ie.Right.(*ast.StringLiteral); ok && ie.Operator == "+" {
+			return newString(left.Value + right.Value)
+		}
+	case *ast.Boolean:
+		if right, ok := ie.Right.(*ast.Boolean); ok {
+			switch ie.Operator {
+			case "==":
+				return newBoolean(left.Value == right.Value)
+			case "!=":
+				return newBoolean(left.Value != right.Value)
+			}
+		}
+	}
+	return nil
+}
+
+func foldIntegerInfix(operator string, left, right int64) ast.Expression {
+	switch operator {
+	case "+":
+		return newInteger(left + right)
+	case "-":
+		return newInteger(left - right)
+	case "*":
+		return newInteger(left * right)
+	case "/":
+		// leave division by zero for the evaluator to deal with at runtime
+		if right == 0 {
+			return nil
+		}
+		return newInteger(left / right)
+	case "<":
+		return newBoolean(left < right)
+	case ">":
+		return newBoolean(left > right)
+	case "==":
+		return newBoolean(left == right)
+	case "!=":
+		return newBoolean(left != right)
+	}
+	return nil
+}
+
+func newInteger(value int64) *ast.IntegerLiteral {
+	literal := strconv.FormatInt(value, 10)
+	return &ast.IntegerLiteral{Token: token.Token{Type: token.INT, Literal: literal}, Value: value}
+}
+
+func newString(value string) *ast.StringLiteral {
+	return &ast.StringLiteral{Token: token.Token{Type: token.STRING, Literal: value}, Value: value}
+}
+
+func newBoolean(value bool) *ast.Boolean {
+	if value {
+		return &ast.Boolean{Token: token.Token{Type: token.TRUE, Literal: "notout"}, Value: true}
+	}
+	return &ast.Boolean{Token: token.Token{Type: token.FALSE, Literal: "out"}, Value: false}
+}
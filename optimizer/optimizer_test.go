@@ -0,0 +1,169 @@
+package optimizer
+
+import (
+	"CricLang/ast"
+	"CricLang/evaluator"
+	"CricLang/lexer"
+	"CricLang/object"
+	"CricLang/parser"
+	"testing"
+)
+
+func TestConstantFolding(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 + 2 * 3", "7"},
+		{"-5 + 10", "5"},
+		{"!notout", "out"},
+		{"!5", "out"},
+		{"notout == out", "out"},
+		{"1 < 2 == notout", "notout"},
+		{`"Hello" + " " + "World"`, "Hello World"},
+		{"x + 2 * 3", "(x + 6)"},
+		{"10 / 0", "(10 / 0)"},
+		{`"a" - "b"`, "(a - b)"},
+		{"5 + notout", "(5 + notout)"},
+		{"add(1 + 1, [2 * 2][0])", "add(2, ([4][0]))"},
+		{"player x = 3 * 3;", "player x = 9;"},
+		{"a.b = 2 * 3", "(a.b = 6)"},
+		{"squad A(x) { player f = field() { self.x + 1 * 2 }; }", "squad A(x) {player f = field()(self.x + 2);}"},
+		{"player [a, b] = [1 + 1, 2];", "player [a, b] = [2, 2];"},
+		{"umpire (1 + 1) { -2 -> 2 * 2; n appeal (n > 1 + 1) -> n }", "umpire (2) {(-2) -> 4; n appeal (n > 2) -> n}"},
+	}
+
+	for _, tt := range tests {
+		program := parse(t, tt.input)
+		Optimize(program)
+
+		if program.String() != tt.expected {
+			t.Errorf("wrong optimization for %q. expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+}
+
+func TestAppealCollapsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"appeal (notout) { 10 } appealrejected { 20 }", "10"},
+		{"appeal (1 > 2) { 10 } appealrejected { 20 }", "20"},
+		{"appeal (x) { 10 } appealrejected { 20 }", "ifx 10else20"},
+		{"appeal (out) { 10 }; 5", "5"},
+		{"appeal (out) { 10 }", "ifout "},
+		{"appeal (notout) { player a = 1; a }; a", "player a = 1;aa"},
+		{"player y = appeal (out) { 1 } appealrejected { player b = 2; b };", "player y = ifout elseplayer b = 2;b;"},
+	}
+
+	for _, tt := range tests {
+		program := parse(t, tt.input)
+		Optimize(program)
+
+		if program.String() != tt.expected {
+			t.Errorf("wrong optimization for %q. expected=%q, got=%q", tt.input, tt.expected, program.String())
+		}
+	}
+}
+
+func TestUnreachableStatements(t *testing.T) {
+	input := `
+	player f = field(x) {
+		signaldecision x;
+		x + 1;
+		x + 2;
+	};
+	signaldecision f(1);
+	99;
+	`
+
+	program := parse(t, input)
+	report := Optimize(program)
+
+	expected := "player f = field(x)signaldecision x;;signaldecision f(1);"
+	if program.String() != expected {
+		t.Errorf("wrong optimization. expected=%q, got=%q", expected, program.String())
+	}
+
+	dropped := 0
+	for _, rm := range report.Removals {
+		if rm.Kind == UNREACHABLE_DROPPED {
+			dropped++
+		}
+	}
+	if dropped != 3 {
+		t.Errorf("wrong number of dropped statements. want=3, got=%d (%s)", dropped, report)
+	}
+}
+
+func TestReport(t *testing.T) {
+	program := parse(t, "appeal (1 < 2) { 10 } appealrejected { 20 }")
+	report := Optimize(program)
+
+	expected := []Removal{
+		{Kind: CONSTANT_FOLDED, Original: "(1 < 2)", Replacement: "notout"},
+		{Kind: BRANCH_COLLAPSED, Original: "ifnotout 10else20", Replacement: "10"},
+	}
+
+	if len(report.Removals) != len(expected) {
+		t.Fatalf("wrong number of removals. want=%d, got=%d (%s)", len(expected), len(report.Removals), report)
+	}
+	for i, want := range expected {
+		if report.Removals[i] != want {
+			t.Errorf("removal %d wrong. want=%+v, got=%+v", i, want, report.Removals[i])
+		}
+	}
+}
+
+func TestEvaluationUnchanged(t *testing.T) {
+	inputs := []string{
+		"(5 + 10 * 2 + 15 / 3) * 2 + -10",
+		"appeal (1 > 2) { 10 }",
+		"5; appeal (out) { 10 }; 6",
+		"appeal (notout) { signaldecision 10; } 5",
+		"player f = field(x) { appeal (notout) { signaldecision x * 2; } x }; f(4)",
+		"player f = field(x) { signaldecision x; 1 / 0 }; f(3)",
+		`"crick" + "et"`,
+		"5 + notout",
+		"-notout",
+		"player a = [1, 2 * 3]; a[2 - 1]",
+		"appeal (notout) { player z = 7; } z",
+		"quote(1 + 2 * 3)",
+		`{"runs": 4 * 6, "out": 1 == 1}["runs"]`,
+		"quote(appeal (notout) { unquote(4 - 1) })",
+	}
+
+	for _, input := range inputs {
+		want := testEval(parse(t, input))
+
+		program := parse(t, input)
+		Optimize(program)
+		got := testEval(program)
+
+		if inspect(want) != inspect(got) {
+			t.Errorf("evaluation changed for %q. want=%s, got=%s", input, inspect(want), inspect(got))
+		}
+	}
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors for %q: %v", input, p.Errors())
+	}
+	return program
+}
+
+func testEval(program *ast.Program) object.Object {
+	return evaluator.Eval(program, object.NewEnvironment())
+}
+
+func inspect(obj object.Object) string {
+	if obj == nil {
+		return "<nil>"
+	}
+	return string(obj.Type()) + ": " + obj.Inspect()
+}
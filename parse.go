@@ -0,0 +1,70 @@
+package main
+
+import (
+	"CricLang/ast"
+	"CricLang/lexer"
+	"CricLang/parser"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func parseCommand(args []string) int {
+	flags := flag.NewFlagSet("parse", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the AST as JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: criclang parse [--json] file.cric")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	program, ok := parseFile(flags.Arg(0))
+	if !ok {
+		return 1
+	}
+
+	if !*asJSON {
+		fmt.Println(program.String())
+		return 0
+	}
+
+	data, err := ast.MarshalIndentJSON(program, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "criclang: %s\n", err)
+		return 1
+	}
+	os.Stdout.Write(data)
+	fmt.Println()
+	return 0
+}
+
+// parseFile reads and parses a source file, reporting any problems to stderr.
+func parseFile(path string) (*ast.Program, bool) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "criclang: %s\n", err)
+		return nil, false
+	}
+
+	p := parser.New(lexer.New(string(source)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		printParserErrors(os.Stderr, path, p.Diagnostics())
+		return nil, false
+	}
+	return program, true
+}
+
+func printParserErrors(out io.Writer, path string, diagnostics []parser.Diagnostic) {
+	fmt.Fprintln(out, "Ben Stokes!!!!")
+	for _, d := range diagnostics {
+		fmt.Fprintf(out, "%s:%s\n", path, d.String())
+	}
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"CricLang/token"
+	"fmt"
+	"strings"
+)
+
+// Diagnostic is a single parse error together with the source line it
+// points into.
+type Diagnostic struct {
+	Line    int
+	Column  int
+	Message string
+	Source  string
+}
+
+func (d Diagnostic) Error() string {
+	return fmt.Sprintf("%d:%d: %s", d.Line, d.Column, d.Message)
+}
+
+// Snippet quotes the offending source line with a caret under the column
+// the diagnostic points at.
+func (d Diagnostic) Snippet() string {
+	if d.Source == "" && d.Column <= 1 {
+		return ""
+	}
+
+	var caret strings.Builder
+	for i := 0; i < d.Column-1; i++ {
+		// keep tabs so the caret lines up however the terminal renders them
+		if i < len(d.Source) && d.Source[i] == '\t' {
+			caret.WriteByte('\t')
+		} else {
+			caret.WriteByte(' ')
+		}
+	}
+	caret.WriteByte('^')
+
+	return d.Source + "\n" + caret.String()
+}
+
+// String renders the diagnostic with its position, message and snippet.
+func (d Diagnostic) String() string {
+	snippet := d.Snippet()
+	if snippet == "" {
+		return d.Error()
+	}
+	return d.Error() + "\n" + snippet
+}
+
+// describeToken names a token the way a CricLang programmer would read it.
+func describeToken(t token.Token) string {
+	switch t.Type {
+	case token.EOF:
+		return "end of input"
+	case token.IDENT:
+		return fmt.Sprintf("identifier `%s`", t.Literal)
+	case token.INT:
+		return fmt.Sprintf("number `%s`", t.Literal)
+	case token.STRING:
+		return fmt.Sprintf("string %q", t.Literal)
+	default:
+		return "`" + t.Literal + "`"
+	}
+}
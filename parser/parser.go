@@ -11,16 +11,18 @@ import (
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN      // self.runs = x
 	EQUALS      // ==
 	LESSGREATER // > or <
 	SUM         // +
 	PRODUCT     // *
 	PREFIX      // -X or !X
 	CALL        // function()
-	INDEX       // array[index]
+	INDEX       // array[index] or card.runs
 )
 
 var precedences = map[token.TokenType]int{
+	token.ASSIGN:   ASSIGN,
 	token.EQ:       EQUALS,
 	token.NOT_EQ:   EQUALS,
 	token.LT:       LESSGREATER,
@@ -31,6 +33,7 @@ var precedences = map[token.TokenType]int{
 	token.ASTERISK: PRODUCT,
 	token.LPAREN:   CALL,
 	token.LBRACKET: INDEX,
+	token.DOT:      INDEX,
 }
 
 type Parser struct {
@@ -39,7 +42,8 @@ type Parser struct {
 	curToken  token.Token
 	peekToken token.Token
 
-	errors []string
+	diagnostics []Diagnostic
+	panicking   bool // set after an error until the parser resynchronizes
 
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
@@ -47,8 +51,8 @@ type Parser struct {
 
 func New(l *lexer.Lexer) *Parser {
 	p := &Parser{
-		l:      l,
-		errors: []string{},
+		l:           l,
+		diagnostics: []Diagnostic{},
 	}
 
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
@@ -63,6 +67,9 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.FUNCTION, p.parseFieldLiteral)
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
+	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
+	p.registerPrefix(token.MACRO, p.parseMacroLiteral)
+	p.registerPrefix(token.UMPIRE, p.parseUmpireExpression)
 
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
@@ -75,6 +82,8 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.GT, p.parseInfixExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.DOT, p.parseDotExpression)
+	p.registerInfix(token.ASSIGN, p.parseAssignExpression)
 
 	// reading 2 tokens together so curToken and peekToken are both set
 	p.nextToken()
@@ -94,7 +103,11 @@ func (p *Parser) ParseProgram() *ast.Program {
 
 	for p.curToken.Type != token.EOF {
 		stmt := p.parseStatement()
-		if stmt != nil {
+		if p.panicking {
+			// a stray '}' at the top level has nothing to close, so it is
+			// skipped along with the rest of the broken statement
+			p.synchronize()
+		} else if stmt != nil {
 			program.Statements = append(program.Statements, stmt)
 		}
 		p.nextToken()
@@ -102,12 +115,49 @@ func (p *Parser) ParseProgram() *ast.Program {
 	return program
 }
 
+// synchronize skips the rest of a statement that failed to parse so that
+// one mistake is reported once instead of as a cascade. It stops on the ';'
+// that ends the statement, on the '}' closing a block the statement opened,
+// or on the '}' of the enclosing block, which it leaves for the caller and
+// reports by returning true.
+func (p *Parser) synchronize() bool {
+	p.panicking = false
+	depth := 0
+
+	for {
+		switch p.curToken.Type {
+		case token.EOF:
+			return true
+		case token.SEMICOLON:
+			if depth == 0 {
+				return false
+			}
+		case token.LBRACE:
+			depth++
+		case token.RBRACE:
+			if depth == 0 {
+				return true
+			}
+			depth--
+			if depth == 0 && !p.peekTokenIs(token.APPEALREJECTED_ELSE) {
+				if p.peekTokenIs(token.SEMICOLON) {
+					p.nextToken()
+				}
+				return false
+			}
+		}
+		p.nextToken()
+	}
+}
+
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.curToken.Type {
 	case token.PLAYER:
 		return p.parsePlayerStatement()
 	case token.SIGNALDECISION_RETURN:
 		return p.parseSignalDecisionStatement()
+	case token.SQUAD:
+		return p.parseSquadStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -116,21 +166,29 @@ func (p *Parser) parseStatement() ast.Statement {
 func (p *Parser) parsePlayerStatement() *ast.PlayerStatement {
 	stmt := &ast.PlayerStatement{Token: p.curToken}
 
-	if !p.expectPeek(token.IDENT) {
-		return nil
-	}
+	if p.peekTokenIs(token.LBRACKET) || p.peekTokenIs(token.LBRACE) {
+		p.nextToken()
+		stmt.Pattern = p.parsePattern(map[string]bool{})
+		if !p.expectPeek(token.ASSIGN, "`=` after the pattern") {
+			return nil
+		}
+	} else {
+		if !p.expectPeek(token.IDENT, "a player name after `player`") {
+			return nil
+		}
 
-	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
-	if !p.expectPeek(token.ASSIGN) {
-		return nil
+		if !p.expectPeek(token.ASSIGN, "`=` after the player name") {
+			return nil
+		}
 	}
 
 	p.nextToken()
 
 	stmt.Value = p.parseExpression(LOWEST)
 
-	for !p.curTokenIs(token.SEMICOLON) {
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 	return stmt
@@ -141,7 +199,8 @@ func (p *Parser) parseSignalDecisionStatement() *ast.SignalDecisionStatement {
 	p.nextToken()
 
 	stmt.SignalDecisionValue = p.parseExpression(LOWEST)
-	for !p.curTokenIs(token.SEMICOLON) {
+
+	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
 	return stmt
@@ -192,8 +251,7 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
 	if err != nil {
-		msg := fmt.Sprintf("couldn't parse %q as integer", p.curToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.errorAt(p.curToken, "number %s is too big to fit in an integer", p.curToken.Literal)
 		return nil
 	}
 	lit.Value = value
@@ -229,7 +287,7 @@ func (p *Parser) parseGroupedExpression() ast.Expression {
 
 	exp := p.parseExpression(LOWEST)
 
-	if !p.expectPeek(token.RPAREN) {
+	if !p.expectPeek(token.RPAREN, "`)` to close the bracketed expression") {
 		return nil
 	}
 	return exp
@@ -238,18 +296,18 @@ func (p *Parser) parseGroupedExpression() ast.Expression {
 func (p *Parser) parseAppealIfExpression() ast.Expression {
 	expression := &ast.AppealIfExpression{Token: p.curToken}
 
-	if !p.expectPeek(token.LPAREN) {
+	if !p.expectPeek(token.LPAREN, "`(` to open the `appeal` condition") {
 		return nil
 	}
 
 	p.nextToken()
 	expression.Condition = p.parseExpression(LOWEST)
 
-	if !p.expectPeek(token.RPAREN) {
+	if !p.expectPeek(token.RPAREN, "`)` to close the `appeal` condition") {
 		return nil
 	}
 
-	if !p.expectPeek(token.LBRACE) {
+	if !p.expectPeek(token.LBRACE, "a `{` block after the `appeal` condition") {
 		return nil
 	}
 
@@ -258,7 +316,7 @@ func (p *Parser) parseAppealIfExpression() ast.Expression {
 	if p.peekTokenIs(token.APPEALREJECTED_ELSE) {
 		p.nextToken()
 
-		if !p.expectPeek(token.LBRACE) {
+		if !p.expectPeek(token.LBRACE, "a `{` block after `appealrejected`") {
 			return nil
 		}
 
@@ -271,29 +329,40 @@ func (p *Parser) parseAppealIfExpression() ast.Expression {
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{Token: p.curToken}
 	block.Statements = []ast.Statement{}
+	reported := len(p.diagnostics)
 
 	p.nextToken()
 
 	for !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
 		stmt := p.parseStatement()
-		if stmt != nil {
+		if p.panicking {
+			if p.synchronize() {
+				break
+			}
+		} else if stmt != nil {
 			block.Statements = append(block.Statements, stmt)
 		}
 		p.nextToken()
 	}
+
+	// an unclosed block is usually the fallout of an error inside it
+	if p.curTokenIs(token.EOF) && len(p.diagnostics) == reported {
+		p.errorAt(p.curToken, "expected `}` to close the block opened on line %d, got end of input", block.Token.Line)
+	}
+	block.EndToken = p.curToken
 	return block
 }
 
 func (p *Parser) parseFieldLiteral() ast.Expression {
 	lit := &ast.FieldLiteral{Token: p.curToken}
 
-	if !p.expectPeek(token.LPAREN) {
+	if !p.expectPeek(token.LPAREN, "a `field` parameter list") {
 		return nil
 	}
 
-	lit.Parameters = p.parseFieldParameters()
+	lit.Parameters = p.parseFieldParameterPatterns()
 
-	if !p.expectPeek(token.LBRACE) {
+	if !p.expectPeek(token.LBRACE, "a `{` block for the `field` body") {
 		return nil
 	}
 
@@ -301,7 +370,26 @@ func (p *Parser) parseFieldLiteral() ast.Expression {
 	return lit
 }
 
-func (p *Parser) parseFieldParameters() []*ast.Identifier {
+func (p *Parser) parseMacroLiteral() ast.Expression {
+	lit := &ast.MacroLiteral{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN, "a `macro` parameter list") {
+		return nil
+	}
+
+	lit.Parameters = p.parseFieldParameters("macro")
+
+	if !p.expectPeek(token.LBRACE, "a `{` block for the `macro` body") {
+		return nil
+	}
+
+	lit.Body = p.parseBlockStatement()
+	return lit
+}
+
+// parseFieldParameters parses the parameter list of a field or macro
+// literal; kind names which one in error messages.
+func (p *Parser) parseFieldParameters(kind string) []*ast.Identifier {
 	identifiers := []*ast.Identifier{}
 
 	if p.peekTokenIs(token.RPAREN) {
@@ -309,28 +397,67 @@ func (p *Parser) parseFieldParameters() []*ast.Identifier {
 		return identifiers
 	}
 
-	p.nextToken()
+	if !p.expectPeek(token.IDENT, "a parameter name") {
+		return nil
+	}
 
 	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 	identifiers = append(identifiers, ident)
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
-		p.nextToken()
+		if !p.expectPeek(token.IDENT, "a parameter name after `,`") {
+			return nil
+		}
 		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 		identifiers = append(identifiers, ident)
 	}
 
-	if !p.expectPeek(token.RPAREN) {
+	if !p.expectPeek(token.RPAREN, "`)` to close the `"+kind+"` parameter list") {
 		return nil
 	}
 
 	return identifiers
 }
 
+// parseFieldParameterPatterns parses the parameter list of a field literal,
+// where a parameter can also be an array or hash pattern.
+func (p *Parser) parseFieldParameterPatterns() []ast.Expression {
+	params := []ast.Expression{}
+
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		return params
+	}
+
+	params = append(params, p.parseParameter("a parameter name"))
+
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		params = append(params, p.parseParameter("a parameter name after `,`"))
+	}
+
+	if p.panicking || !p.expectPeek(token.RPAREN, "`)` to close the `field` parameter list") {
+		return nil
+	}
+
+	return params
+}
+
+func (p *Parser) parseParameter(expected string) ast.Expression {
+	if p.peekTokenIs(token.LBRACKET) || p.peekTokenIs(token.LBRACE) {
+		p.nextToken()
+		return p.parsePattern(map[string]bool{})
+	}
+	if !p.expectPeek(token.IDENT, expected) {
+		return nil
+	}
+	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+}
+
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	exp := &ast.CallExpression{Token: p.curToken, Function: function}
-	exp.Arguments = p.parseExpressionList(token.RPAREN)
+	exp.Arguments = p.parseExpressionList(token.RPAREN, "`)` to close the call arguments")
 	return exp
 }
 
@@ -339,8 +466,11 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 }
 
 func (p *Parser) noPrefixParseFnError(t token.TokenType) {
-	msg := fmt.Sprintf("no prefix parse function for %s found", t)
-	p.errors = append(p.errors, msg)
+	if t == token.ILLEGAL {
+		p.errorAt(p.curToken, "unexpected character %s", describeToken(p.curToken))
+		return
+	}
+	p.errorAt(p.curToken, "expected an expression, got %s", describeToken(p.curToken))
 }
 
 func (p *Parser) curTokenIs(t token.TokenType) bool {
@@ -351,23 +481,60 @@ func (p *Parser) peekTokenIs(t token.TokenType) bool {
 	return p.peekToken.Type == t
 }
 
-func (p *Parser) expectPeek(t token.TokenType) bool {
+// expectPeek advances if the next token has type t. Otherwise it reports
+// that the construct described by expected is missing.
+func (p *Parser) expectPeek(t token.TokenType, expected string) bool {
 	if p.peekTokenIs(t) {
 		p.nextToken()
 		return true
 	} else {
-		p.peekError(t)
+		p.peekError(expected)
 		return false
 	}
 }
 
+// Errors returns one line per diagnostic, prefixed with its position.
 func (p *Parser) Errors() []string {
-	return p.errors
+	errors := []string{}
+	for _, d := range p.diagnostics {
+		errors = append(errors, d.Error())
+	}
+	return errors
+}
+
+// Diagnostics returns the parse errors with enough context to point at
+// the offending source.
+func (p *Parser) Diagnostics() []Diagnostic {
+	return p.diagnostics
+}
+
+func (p *Parser) peekError(expected string) {
+	p.errorAt(p.peekToken, "expected %s, got %s", expected, describeToken(p.peekToken))
+}
+
+// errorAt records a diagnostic at tok unless the parser is already
+// recovering from an earlier error in the same statement.
+func (p *Parser) errorAt(tok token.Token, format string, a ...interface{}) {
+	if p.panicking {
+		return
+	}
+	p.panicking = true
+
+	p.diagnostics = append(p.diagnostics, Diagnostic{
+		Line:    tok.Line,
+		Column:  tok.Column,
+		Message: fmt.Sprintf(format, a...),
+		Source:  p.l.SourceLine(tok.Line),
+	})
 }
 
-func (p *Parser) peekError(t token.TokenType) {
-	msg := fmt.Sprintf("expected next token to be %s, got=%s", t, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
+// Precedence returns how tightly an infix operator token binds, or LOWEST
+// for tokens that aren't infix operators.
+func Precedence(t token.TokenType) int {
+	if p, ok := precedences[t]; ok {
+		return p
+	}
+	return LOWEST
 }
 
 func (p *Parser) peekPrecedence() int {
@@ -400,11 +567,38 @@ type (
 func (p *Parser) parseArrayLiteral() ast.Expression {
 	array := &ast.ArrayLiteral{Token: p.curToken}
 
-	array.Elements = p.parseExpressionList(token.RBRACKET)
+	array.Elements = p.parseExpressionList(token.RBRACKET, "`]` to close the array")
 	return array
 }
 
-func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
+func (p *Parser) parseHashLiteral() ast.Expression {
+	hash := &ast.HashLiteral{Token: p.curToken, Pairs: []ast.HashPair{}}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		key := p.parseExpression(LOWEST)
+
+		if !p.expectPeek(token.COLON, "`:` after the hash key") {
+			return nil
+		}
+
+		p.nextToken()
+		value := p.parseExpression(LOWEST)
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA, "`,` or `}` in the hash") {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE, "`}` to close the hash") {
+		return nil
+	}
+
+	return hash
+}
+
+func (p *Parser) parseExpressionList(end token.TokenType, expected string) []ast.Expression {
 	list := []ast.Expression{}
 
 	if p.peekTokenIs(end) {
@@ -421,7 +615,7 @@ func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 		list = append(list, p.parseExpression(LOWEST))
 	}
 
-	if !p.expectPeek(end) {
+	if !p.expectPeek(end, expected) {
 		return nil
 	}
 
@@ -434,9 +628,338 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	p.nextToken()
 	exp.Index = p.parseExpression(LOWEST)
 
-	if !p.expectPeek(token.RBRACKET) {
+	if !p.expectPeek(token.RBRACKET, "`]` to close the index") {
+		return nil
+	}
+
+	return exp
+}
+
+func (p *Parser) parseDotExpression(left ast.Expression) ast.Expression {
+	exp := &ast.DotExpression{Token: p.curToken, Left: left}
+
+	if !p.expectPeek(token.IDENT, "a name after `.`") {
+		return nil
+	}
+
+	exp.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	return exp
+}
+
+// parseAssignExpression parses the right-hand side at LOWEST so that
+// a.x = b.x = 1 assigns right to left.
+func (p *Parser) parseAssignExpression(left ast.Expression) ast.Expression {
+	exp := &ast.AssignExpression{Token: p.curToken, Target: left}
+
+	if _, ok := left.(*ast.DotExpression); !ok {
+		p.errorAt(p.curToken, "only a field like `self.runs` can be assigned with `=`; use `player` to bind a name")
+		return nil
+	}
+
+	p.nextToken()
+	exp.Value = p.parseExpression(LOWEST)
+	return exp
+}
+
+// parseSquadStatement parses
+//
+//	squad Opener(partner) extends Batter { player method = field() {...}; }
+//
+// where the field list and the parent are optional.
+func (p *Parser) parseSquadStatement() ast.Statement {
+	stmt := &ast.SquadStatement{Token: p.curToken, Fields: []*ast.Identifier{}}
+
+	if !p.expectPeek(token.IDENT, "a squad name after `squad`") {
+		return nil
+	}
+
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.LPAREN) {
+		p.nextToken()
+		stmt.Fields = p.parseFieldParameters("squad")
+	}
+
+	if p.peekTokenIs(token.EXTENDS) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT, "a squad name after `extends`") {
+			return nil
+		}
+		stmt.Parent = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if !p.expectPeek(token.LBRACE, "a `{` block for the squad's methods") {
+		return nil
+	}
+
+	stmt.Body = p.parseBlockStatement()
+	p.checkSquadMethods(stmt)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+// checkSquadMethods reports anything in a squad body other than a player
+// bound to a field literal. The body has already parsed, so there is
+// nothing to skip and the parser carries on without resynchronizing.
+func (p *Parser) checkSquadMethods(stmt *ast.SquadStatement) {
+	for _, s := range stmt.Body.Statements {
+		if method, ok := s.(*ast.PlayerStatement); ok {
+			if method.Name == nil {
+				p.errorAt(method.Token, "a method of squad %s must be bound to a name, not a pattern", stmt.Name.Value)
+			} else if _, ok := method.Value.(*ast.FieldLiteral); !ok {
+				p.errorAt(method.Name.Token, "method %s of squad %s must be a `field`", method.Name.Value, stmt.Name.Value)
+			}
+		} else {
+			tok := statementToken(s)
+			p.errorAt(tok, "expected `player` to declare a method of squad %s, got %s", stmt.Name.Value, describeToken(tok))
+		}
+		p.panicking = false
+	}
+}
+
+// parseUmpireExpression parses
+//
+//	umpire (ball) { 4 -> "four"; [x, ...rest] appeal (x > 0) -> x; _ -> 0 }
+//
+// An arm that fails to parse is reported and skipped, the way a broken
+// statement is skipped in a block, so the arms after it are still checked.
+func (p *Parser) parseUmpireExpression() ast.Expression {
+	exp := &ast.UmpireExpression{Token: p.curToken, Arms: []ast.UmpireArm{}}
+
+	if !p.expectPeek(token.LPAREN, "`(` after `umpire`") {
 		return nil
 	}
 
+	p.nextToken()
+	exp.Subject = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN, "`)` to close the `umpire` subject") {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE, "a `{` with the `umpire` arms") {
+		return nil
+	}
+	open := p.curToken
+	reported := len(p.diagnostics)
+
+	p.nextToken()
+
+	for !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
+		arm := p.parseUmpireArm()
+		if p.panicking {
+			if p.synchronize() {
+				break
+			}
+		} else {
+			exp.Arms = append(exp.Arms, arm)
+		}
+		p.nextToken()
+	}
+
+	if p.curTokenIs(token.EOF) {
+		if len(p.diagnostics) == reported {
+			p.errorAt(p.curToken, "expected `}` to close the `umpire` opened on line %d, got end of input", open.Line)
+		}
+		return nil
+	}
+	exp.EndToken = p.curToken
+	if len(exp.Arms) == 0 && len(p.diagnostics) == reported {
+		p.errorAt(exp.Token, "`umpire` needs at least one arm")
+		// the arms have been read, so there is nothing left to skip
+		p.panicking = false
+	}
 	return exp
 }
+
+// parseUmpireArm parses `pattern appeal (guard) -> body` and the `;` after
+// it, which the last arm may leave off.
+func (p *Parser) parseUmpireArm() ast.UmpireArm {
+	arm := ast.UmpireArm{Pattern: p.parsePattern(map[string]bool{})}
+	if p.panicking {
+		return arm
+	}
+
+	if p.peekTokenIs(token.APPEAL_IF) {
+		p.nextToken()
+		if !p.expectPeek(token.LPAREN, "`(` to open the guard") {
+			return arm
+		}
+		p.nextToken()
+		arm.Guard = p.parseExpression(LOWEST)
+		if !p.expectPeek(token.RPAREN, "`)` to close the guard") {
+			return arm
+		}
+	}
+
+	if !p.expectPeek(token.ARROW, "`->` after the pattern") {
+		return arm
+	}
+
+	p.nextToken()
+	arm.Body = p.parseExpression(LOWEST)
+
+	if !p.peekTokenIs(token.RBRACE) {
+		p.expectPeek(token.SEMICOLON, "`;` or `}` after the `umpire` arm")
+	}
+	return arm
+}
+
+// parsePattern parses a literal, a name to bind, `_` (which matches
+// anything and binds nothing), or an array or hash of patterns. bound holds
+// the names bound so far in the same pattern.
+func (p *Parser) parsePattern(bound map[string]bool) ast.Expression {
+	switch p.curToken.Type {
+	case token.INT:
+		return p.parseIntegerLiteral()
+	case token.STRING:
+		return p.parseStringLiteral()
+	case token.TRUE, token.FALSE:
+		return p.parseBoolean()
+	case token.MINUS:
+		exp := &ast.PrefixExpression{Token: p.curToken, Operator: p.curToken.Literal}
+		if !p.expectPeek(token.INT, "a number after `-` in the pattern") {
+			return nil
+		}
+		exp.Right = p.parseIntegerLiteral()
+		return exp
+	case token.IDENT:
+		return p.parsePatternName(bound)
+	case token.LBRACKET:
+		return p.parseArrayPattern(bound)
+	case token.LBRACE:
+		return p.parseHashPattern(bound)
+	}
+	p.errorAt(p.curToken, "expected a pattern, got %s", describeToken(p.curToken))
+	return nil
+}
+
+func (p *Parser) parsePatternName(bound map[string]bool) *ast.Identifier {
+	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	if ident.Value != "_" {
+		if bound[ident.Value] {
+			p.errorAt(p.curToken, "%s is bound twice in the pattern", ident.Value)
+		}
+		bound[ident.Value] = true
+	}
+	return ident
+}
+
+// parseArrayPattern parses [a, b, ...rest], where the rest is optional and
+// must come last.
+func (p *Parser) parseArrayPattern(bound map[string]bool) ast.Expression {
+	array := &ast.ArrayLiteral{Token: p.curToken, Elements: []ast.Expression{}}
+
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+
+		if p.curTokenIs(token.ELLIPSIS) {
+			rest := &ast.RestElement{Token: p.curToken}
+			if !p.expectPeek(token.IDENT, "a name after `...`") {
+				return nil
+			}
+			rest.Name = p.parsePatternName(bound)
+			array.Elements = append(array.Elements, rest)
+			if !p.expectPeek(token.RBRACKET, "`]` after `..."+rest.Name.Value+"`, which must come last") {
+				return nil
+			}
+			return array
+		}
+
+		array.Elements = append(array.Elements, p.parsePattern(bound))
+		if p.panicking {
+			return nil
+		}
+
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA, "`,` or `]` in the array pattern") {
+			return nil
+		}
+	}
+
+	p.nextToken()
+	return array
+}
+
+// parseHashPattern parses {"runs": pattern, balls}. A bare name is short
+// for a string key of the same name bound to that name.
+func (p *Parser) parseHashPattern(bound map[string]bool) ast.Expression {
+	hash := p.parseHashPatternPairs(bound)
+	if p.panicking {
+		// step past the pattern's `}` so that recovery doesn't take it for
+		// the one closing the umpire
+		p.skipPastBrace()
+		return nil
+	}
+	return hash
+}
+
+func (p *Parser) parseHashPatternPairs(bound map[string]bool) *ast.HashLiteral {
+	hash := &ast.HashLiteral{Token: p.curToken, Pairs: []ast.HashPair{}}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+
+		switch p.curToken.Type {
+		case token.STRING:
+			key := p.parseStringLiteral()
+			if !p.expectPeek(token.COLON, "`:` after the key in the hash pattern") {
+				return nil
+			}
+			p.nextToken()
+			value := p.parsePattern(bound)
+			if p.panicking {
+				return nil
+			}
+			hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
+		case token.IDENT:
+			key := &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+			hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: p.parsePatternName(bound)})
+		default:
+			p.errorAt(p.curToken, "expected a key in the hash pattern, got %s", describeToken(p.curToken))
+			return nil
+		}
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA, "`,` or `}` in the hash pattern") {
+			return nil
+		}
+	}
+
+	p.nextToken()
+	return hash
+}
+
+// skipPastBrace moves to the token after the next `}` that isn't matched by
+// a `{` on the way.
+func (p *Parser) skipPastBrace() {
+	depth := 0
+	for !p.curTokenIs(token.EOF) {
+		switch p.curToken.Type {
+		case token.LBRACE:
+			depth++
+		case token.RBRACE:
+			if depth == 0 {
+				p.nextToken()
+				return
+			}
+			depth--
+		}
+		p.nextToken()
+	}
+}
+
+func statementToken(stmt ast.Statement) token.Token {
+	switch stmt := stmt.(type) {
+	case *ast.PlayerStatement:
+		return stmt.Token
+	case *ast.SignalDecisionStatement:
+		return stmt.Token
+	case *ast.ExpressionStatement:
+		return stmt.Token
+	case *ast.SquadStatement:
+		return stmt.Token
+	}
+	return token.Token{}
+}
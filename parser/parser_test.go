@@ -702,6 +702,37 @@ func TestFieldParameterParsing(t *testing.T) {
 	}
 }
 
+func TestDestructuringPatterns(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"player [striker, nonStriker] = pair;", "player [striker, nonStriker] = pair;"},
+		{"player [a, [b, ...c], ...d] = x;", "player [a, [b, ...c], ...d] = x;"},
+		{`player {runs, "batter": [name, _]} = card;`, "player {runs: runs, batter: [name, _]} = card;"},
+		{"field([a, b], {c}, d) { a }", "field([a, b], {c: c}, d)a"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	p := New(lexer.New("player [a, b] = pair;"))
+	stmt := p.ParseProgram().Statements[0].(*ast.PlayerStatement)
+	if stmt.Name != nil {
+		t.Errorf("destructuring player has a name. got=%s", stmt.Name)
+	}
+	if _, ok := stmt.Pattern.(*ast.ArrayLiteral); !ok {
+		t.Errorf("stmt.Pattern is not *ast.ArrayLiteral. got=%T", stmt.Pattern)
+	}
+}
+
 func TestCallExpressionParsing(t *testing.T) {
 	input := "add(1, 2 * 3, 4 + 5);"
 
@@ -865,3 +896,397 @@ func TestParsingIndexExpression(t *testing.T) {
 		return
 	}
 }
+
+func TestParserErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"player = 5;", []string{"1:8: expected a player name after `player`, got `=`"}},
+		{"player x 5;", []string{"1:10: expected `=` after the player name, got number `5`"}},
+		{"field x", []string{"1:7: expected a `field` parameter list, got identifier `x`"}},
+		{"field(1) {}", []string{"1:7: expected a parameter name, got number `1`"}},
+		{"field(x, ) {}", []string{"1:10: expected a parameter name after `,`, got `)`"}},
+		{"appeal x", []string{"1:8: expected `(` to open the `appeal` condition, got identifier `x`"}},
+		{"appeal (x) x", []string{"1:12: expected a `{` block after the `appeal` condition, got identifier `x`"}},
+		{"appeal (x) {} appealrejected 1", []string{"1:30: expected a `{` block after `appealrejected`, got number `1`"}},
+		{"add(1, 2", []string{"1:9: expected `)` to close the call arguments, got end of input"}},
+		{"a[1", []string{"1:4: expected `]` to close the index, got end of input"}},
+		{"(1 + 2", []string{"1:7: expected `)` to close the bracketed expression, got end of input"}},
+		{"player x = ;", []string{"1:12: expected an expression, got `;`"}},
+		{"5 + @", []string{"1:5: unexpected character `@`"}},
+		{"99999999999999999999", []string{"1:1: number 99999999999999999999 is too big to fit in an integer"}},
+		{"field(x) { x", []string{"1:13: expected `}` to close the block opened on line 1, got end of input"}},
+		{`{"a" 1}`, []string{"1:6: expected `:` after the hash key, got number `1`"}},
+		{`{"a": 1 "b": 2}`, []string{"1:9: expected `,` or `}` in the hash, got string \"b\""}},
+		{"x = 5;", []string{"1:3: only a field like `self.runs` can be assigned with `=`; use `player` to bind a name"}},
+		{"card.1", []string{"1:6: expected a name after `.`, got number `1`"}},
+		{"squad {}", []string{"1:7: expected a squad name after `squad`, got `{`"}},
+		{"squad A extends {}", []string{"1:17: expected a squad name after `extends`, got `{`"}},
+		{"squad A(x) B {}", []string{"1:12: expected a `{` block for the squad's methods, got identifier `B`"}},
+		{"squad A { player x = 5; }", []string{"1:18: method x of squad A must be a `field`"}},
+		{"squad A { x; player y = 1; }", []string{
+			"1:11: expected `player` to declare a method of squad A, got identifier `x`",
+			"1:21: method y of squad A must be a `field`",
+		}},
+		{"umpire x", []string{"1:8: expected `(` after `umpire`, got identifier `x`"}},
+		{"umpire (x) 1", []string{"1:12: expected a `{` with the `umpire` arms, got number `1`"}},
+		{"umpire (x) {}", []string{"1:1: `umpire` needs at least one arm"}},
+		{"umpire (x) { 1 2 }", []string{"1:16: expected `->` after the pattern, got number `2`"}},
+		{"umpire (x) { a + 1 -> 2 }", []string{"1:16: expected `->` after the pattern, got `+`"}},
+		{"umpire (x) { f(a) -> 2 }", []string{"1:15: expected `->` after the pattern, got `(`"}},
+		{"umpire (x) { 1 -> 1 2 -> 2 }", []string{"1:21: expected `;` or `}` after the `umpire` arm, got number `2`"}},
+		{"umpire (x) { [a, a] -> a }", []string{"1:18: a is bound twice in the pattern"}},
+		{"umpire (x) { [...a, b] -> a }", []string{"1:19: expected `]` after `...a`, which must come last, got `,`"}},
+		{"umpire (x) { {1: a} -> a }", []string{"1:15: expected a key in the hash pattern, got number `1`"}},
+		{"umpire (x) { -a -> a }", []string{"1:15: expected a number after `-` in the pattern, got identifier `a`"}},
+		{"umpire (x) { 1 appeal x -> 1 }", []string{"1:23: expected `(` to open the guard, got identifier `x`"}},
+		{"umpire (x) { (1) -> 1; 2 -> 2; [3 -> 3 }", []string{
+			"1:14: expected a pattern, got `(`",
+			"1:35: expected `,` or `]` in the array pattern, got `->`",
+		}},
+		{"player [a, a] = x;", []string{"1:12: a is bound twice in the pattern"}},
+		{"player [a, 1 + 2] = x;", []string{"1:14: expected `,` or `]` in the array pattern, got `+`"}},
+		{"player {a} x;", []string{"1:12: expected `=` after the pattern, got identifier `x`"}},
+		{"player [a] = x; player {1} = y; player b = 2;", []string{"1:25: expected a key in the hash pattern, got number `1`"}},
+		{"field([a, ...b, c]) {}", []string{"1:15: expected `]` after `...b`, which must come last, got `,`"}},
+		{"squad A { player [f] = field() {}; }", []string{"1:11: a method of squad A must be bound to a name, not a pattern"}},
+		{"umpire (x) { 1 -> 1", []string{"1:20: expected `;` or `}` after the `umpire` arm, got end of input"}},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) != len(tt.expected) {
+			t.Errorf("wrong number of errors for %q. want=%q, got=%q", tt.input, tt.expected, errors)
+			continue
+		}
+		for i, msg := range tt.expected {
+			if errors[i] != msg {
+				t.Errorf("wrong error for %q.\nwant=%q\ngot= %q", tt.input, msg, errors[i])
+			}
+		}
+	}
+}
+
+func TestParserErrorRecovery(t *testing.T) {
+	input := `player x = 5
+player f = field(a b) {
+	a + x;
+};
+appeal (x > 1 {
+	x;
+}
+player ok = [1, 2;
+f(1);
+player y = x * 2;`
+
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+
+	expectedErrors := []string{
+		"2:20: expected `)` to close the `field` parameter list, got identifier `b`",
+		"5:15: expected `)` to close the `appeal` condition, got `{`",
+		"8:18: expected `]` to close the array, got `;`",
+	}
+	errors := p.Errors()
+	if len(errors) != len(expectedErrors) {
+		t.Fatalf("wrong errors.\nwant=%q\ngot= %q", expectedErrors, errors)
+	}
+	for i, msg := range expectedErrors {
+		if errors[i] != msg {
+			t.Errorf("errors[%d] wrong.\nwant=%q\ngot= %q", i, msg, errors[i])
+		}
+	}
+
+	// the statements around the broken ones still parse
+	expected := "player x = 5;f(1)player y = (x * 2);"
+	if program.String() != expected {
+		t.Errorf("wrong program after recovery.\nwant=%q\ngot= %q", expected, program.String())
+	}
+}
+
+func TestDiagnosticSnippet(t *testing.T) {
+	input := "player x = 5;\n\tappeal (x > 1 {\n\t\tx;\n\t}"
+
+	p := New(lexer.New(input))
+	p.ParseProgram()
+
+	diagnostics := p.Diagnostics()
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic. got=%v", p.Errors())
+	}
+
+	expected := "2:16: expected `)` to close the `appeal` condition, got `{`\n" +
+		"\tappeal (x > 1 {\n" +
+		"\t              ^"
+	if diagnostics[0].String() != expected {
+		t.Errorf("wrong diagnostic.\nwant:\n%s\ngot:\n%s", expected, diagnostics[0].String())
+	}
+}
+
+func TestMacroLiteralParsing(t *testing.T) {
+	input := `macro(x, y) { x + y; }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("statement is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+
+	macro, ok := stmt.Expression.(*ast.MacroLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.MacroLiteral. got=%T",
+			stmt.Expression)
+	}
+
+	if len(macro.Parameters) != 2 {
+		t.Fatalf("macro literal parameters wrong. want 2, got=%d\n",
+			len(macro.Parameters))
+	}
+
+	testLiteralExpression(t, macro.Parameters[0], "x")
+	testLiteralExpression(t, macro.Parameters[1], "y")
+
+	if len(macro.Body.Statements) != 1 {
+		t.Fatalf("macro.Body.Statements has not 1 statements. got=%d\n",
+			len(macro.Body.Statements))
+	}
+
+	bodyStmt, ok := macro.Body.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("macro body stmt is not ast.ExpressionStatement. got=%T",
+			macro.Body.Statements[0])
+	}
+
+	testInfixExpression(t, bodyStmt.Expression, "x", "+", "y")
+}
+
+func TestParsingHashLiterals(t *testing.T) {
+	input := `{"one": 1, "two": 2, "three": 3}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+	}
+
+	expected := []struct {
+		key   string
+		value int64
+	}{
+		{"one", 1},
+		{"two", 2},
+		{"three", 3},
+	}
+
+	if len(hash.Pairs) != len(expected) {
+		t.Fatalf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
+	}
+
+	for i, pair := range hash.Pairs {
+		literal, ok := pair.Key.(*ast.StringLiteral)
+		if !ok {
+			t.Errorf("key is not ast.StringLiteral. got=%T", pair.Key)
+			continue
+		}
+		if literal.Value != expected[i].key {
+			t.Errorf("key %d wrong. want=%q, got=%q", i, expected[i].key, literal.Value)
+		}
+		testIntegerLiteral(t, pair.Value, expected[i].value)
+	}
+}
+
+func TestParsingEmptyHashLiteral(t *testing.T) {
+	input := "{}"
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(hash.Pairs) != 0 {
+		t.Errorf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
+	}
+}
+
+func TestParsingHashLiteralsWithExpressions(t *testing.T) {
+	input := `{"one": 0 + 1, "two": 10 - 8, "three": 15 / 5}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(hash.Pairs) != 3 {
+		t.Fatalf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
+	}
+
+	testInfixExpression(t, hash.Pairs[0].Value, 0, "+", 1)
+	testInfixExpression(t, hash.Pairs[1].Value, 10, "-", 8)
+	testInfixExpression(t, hash.Pairs[2].Value, 15, "/", 5)
+}
+
+func TestDotAndAssignPrecedence(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a.b", "a.b"},
+		{"a.b.c", "a.b.c"},
+		{"-a.b", "(-a.b)"},
+		{"a.b + c.d * e", "(a.b + (c.d * e))"},
+		{"a.b(1).c", "a.b(1).c"},
+		{"a[0].b", "(a[0]).b"},
+		{`s.split(",").reverse().join("-")`, "s.split(,).reverse().join(-)"},
+		{"self.runs = self.runs + 4", "(self.runs = (self.runs + 4))"},
+		{"a.x = b.x = 1", "(a.x = (b.x = 1))"},
+		{"a.ok = 1 == 2", "(a.ok = (1 == 2))"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestUmpireExpression(t *testing.T) {
+	input := `umpire (ball) {
+	4 -> "four";
+	-1 -> "minus";
+	["W", kind, ...rest] appeal (len(rest) == 0) -> kind;
+	{"runs": [r, _], balls} -> r + balls;
+	_ -> 0
+}`
+
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+	}
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*ast.UmpireExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not *ast.UmpireExpression. got=%T", stmt.Expression)
+	}
+	testIdentifier(t, exp.Subject, "ball")
+
+	expected := []struct {
+		pattern string
+		guard   string
+		body    string
+	}{
+		{"4", "", "four"},
+		{"(-1)", "", "minus"},
+		{"[W, kind, ...rest]", "(len(rest) == 0)", "kind"},
+		{"{runs: [r, _], balls: balls}", "", "(r + balls)"},
+		{"_", "", "0"},
+	}
+	if len(exp.Arms) != len(expected) {
+		t.Fatalf("wrong number of arms. want=%d, got=%d", len(expected), len(exp.Arms))
+	}
+	for i, want := range expected {
+		arm := exp.Arms[i]
+		if arm.Pattern.String() != want.pattern {
+			t.Errorf("arms[%d].Pattern wrong. want=%q, got=%q", i, want.pattern, arm.Pattern.String())
+		}
+		guard := ""
+		if arm.Guard != nil {
+			guard = arm.Guard.String()
+		}
+		if guard != want.guard {
+			t.Errorf("arms[%d].Guard wrong. want=%q, got=%q", i, want.guard, guard)
+		}
+		if arm.Body.String() != want.body {
+			t.Errorf("arms[%d].Body wrong. want=%q, got=%q", i, want.body, arm.Body.String())
+		}
+	}
+
+	shorthand := exp.Arms[3].Pattern.(*ast.HashLiteral).Pairs[1]
+	if key, ok := shorthand.Key.(*ast.StringLiteral); !ok || key.Value != "balls" {
+		t.Errorf("shorthand key is not the string balls. got=%#v", shorthand.Key)
+	}
+	testIdentifier(t, shorthand.Value, "balls")
+}
+
+func TestSquadStatement(t *testing.T) {
+	input := `squad Opener(partner, balls) extends Batter {
+	player strikeRate = field() { self.runs * 100 / self.balls };
+	player face = field(n) { self.balls = self.balls + n; };
+};`
+
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+	}
+	stmt, ok := program.Statements[0].(*ast.SquadStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not *ast.SquadStatement. got=%T", program.Statements[0])
+	}
+
+	if stmt.Name.Value != "Opener" {
+		t.Errorf("stmt.Name wrong. want=Opener, got=%s", stmt.Name.Value)
+	}
+	if stmt.Parent == nil || stmt.Parent.Value != "Batter" {
+		t.Errorf("stmt.Parent wrong. want=Batter, got=%v", stmt.Parent)
+	}
+	if len(stmt.Fields) != 2 {
+		t.Fatalf("wrong number of fields. want=2, got=%d", len(stmt.Fields))
+	}
+	testLiteralExpression(t, stmt.Fields[0], "partner")
+	testLiteralExpression(t, stmt.Fields[1], "balls")
+
+	if len(stmt.Body.Statements) != 2 {
+		t.Fatalf("wrong number of methods. want=2, got=%d", len(stmt.Body.Statements))
+	}
+	for i, name := range []string{"strikeRate", "face"} {
+		testPlayerStatement(t, stmt.Body.Statements[i], name)
+	}
+
+	bare := New(lexer.New("squad Empty {}"))
+	program = bare.ParseProgram()
+	checkParserErrors(t, bare)
+	empty := program.Statements[0].(*ast.SquadStatement)
+	if empty.Parent != nil || len(empty.Fields) != 0 || len(empty.Body.Statements) != 0 {
+		t.Errorf("squad without fields, parent or methods parsed wrong. got=%s", empty)
+	}
+}
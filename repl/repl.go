@@ -1,20 +1,27 @@
 package repl
 
 import (
+	"CricLang/ast"
 	"CricLang/evaluator"
 	"CricLang/lexer"
 	"CricLang/object"
+	"CricLang/optimizer"
 	"CricLang/parser"
 	"bufio"
 	"fmt"
 	"io"
+	"strings"
 )
 
 const PROMPT = ">>"
 
-func Start(in io.Reader, out io.Writer) {
+// Start runs the REPL. Anything the program prints goes to out as well, so
+// runtime.Out is replaced with it.
+func Start(in io.Reader, out io.Writer, runtime *object.Runtime) {
+	runtime.Out = out
 	scanner := bufio.NewScanner(in)
-	env := object.NewEnvironment()
+	env := object.NewRuntimeEnvironment(runtime, "")
+	macroEnv := object.NewRuntimeEnvironment(runtime, "")
 
 	for {
 		fmt.Printf(PROMPT + " ")
@@ -28,10 +35,21 @@ func Start(in io.Reader, out io.Writer) {
 
 		program := p.ParseProgram()
 		if len(p.Errors()) != 0 {
-			printParserErrors(out, p.Errors())
+			printParserErrors(out, p.Diagnostics())
 			continue
 		}
 
+		evaluator.DefineMacros(program, macroEnv)
+		expanded, misfield := evaluator.ExpandMacros(program, macroEnv)
+		if misfield != nil {
+			io.WriteString(out, misfield.Inspect())
+			io.WriteString(out, "\n")
+			continue
+		}
+		program = expanded.(*ast.Program)
+
+		optimizer.Optimize(program)
+
 		evaluated := evaluator.Eval(program, env)
 		if evaluated != nil {
 			io.WriteString(out, evaluated.Inspect())
@@ -40,9 +58,16 @@ func Start(in io.Reader, out io.Writer) {
 	}
 }
 
-func printParserErrors(out io.Writer, errors []string) {
+func printParserErrors(out io.Writer, diagnostics []parser.Diagnostic) {
 	io.WriteString(out, "Ben Stokes!!!! \n")
-	for _, msg := range errors {
-		io.WriteString(out, "\t"+msg+"\n")
+	for _, d := range diagnostics {
+		io.WriteString(out, "\t"+d.Message+"\n")
+		if snippet := d.Snippet(); snippet != "" {
+			io.WriteString(out, indent(snippet, "\t\t")+"\n")
+		}
 	}
 }
+
+func indent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"CricLang/ast"
+	"CricLang/evaluator"
+	"CricLang/object"
+	"CricLang/optimizer"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// listFlag collects the values of a flag that may be repeated.
+type listFlag []string
+
+func (f *listFlag) String() string { return strings.Join(*f, string(filepath.ListSeparator)) }
+
+func (f *listFlag) Set(dir string) error {
+	*f = append(*f, dir)
+	return nil
+}
+
+func runFileCommand(args []string) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	var includes listFlag
+	flags.Var(&includes, "I", "add `dir` to the import search path (repeatable)")
+	seed := seedFlag(flags)
+	allowed := allowDirFlag(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: criclang run [-I dir] [--seed n] [--allow-dir dir] file.cric")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	path := flags.Arg(0)
+	program, ok := parseFile(path)
+	if !ok {
+		return 1
+	}
+
+	runtime := newRuntime(includes)
+	applySeed(runtime, flags, *seed)
+	runtime.FileRoots = *allowed
+	env := object.NewRuntimeEnvironment(runtime, filepath.Dir(path))
+	macroEnv := object.NewRuntimeEnvironment(runtime, filepath.Dir(path))
+
+	evaluator.DefineMacros(program, macroEnv)
+	expanded, misfield := evaluator.ExpandMacros(program, macroEnv)
+	if misfield != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", path, misfield.Inspect())
+		return 1
+	}
+	program = expanded.(*ast.Program)
+	optimizer.Optimize(program)
+
+	if evaluated := evaluator.Eval(program, env); evaluated != nil && evaluated.Type() == object.MISFIELD_ERROR_OBJECT {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", path, evaluated.Inspect())
+		return 1
+	}
+	return 0
+}
+
+// newRuntime builds the interpreter runtime. Imports are searched in the
+// given directories first, then in those listed in $CRICLANG_PATH.
+func newRuntime(includes []string) *object.Runtime {
+	runtime := object.NewRuntime()
+	runtime.IncludePath = append(runtime.IncludePath, includes...)
+	if path := os.Getenv("CRICLANG_PATH"); path != "" {
+		runtime.IncludePath = append(runtime.IncludePath, filepath.SplitList(path)...)
+	}
+	return runtime
+}
+
+func seedFlag(flags *flag.FlagSet) *int64 {
+	return flags.Int64("seed", 0, "seed the random source so runs can be replayed")
+}
+
+// applySeed seeds runtime when --seed was given; otherwise every run is
+// different.
+func applySeed(runtime *object.Runtime, flags *flag.FlagSet, seed int64) {
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			runtime.Seed(seed)
+		}
+	})
+}
+
+func allowDirFlag(flags *flag.FlagSet) *listFlag {
+	var dirs listFlag
+	flags.Var(&dirs, "allow-dir", "let the file builtins use `dir` and everything under it (repeatable)")
+	return &dirs
+}
@@ -0,0 +1,357 @@
+// Package sim simulates limited-overs innings and matches ball by ball.
+// Each delivery's outcome is drawn from the batter's and the bowler's
+// outcome weights, and is recorded on an object.Innings, so a simulated
+// innings has the same scorecard as one scored by hand.
+package sim
+
+import (
+	"CricLang/object"
+	"fmt"
+	"math/rand"
+	"strconv"
+)
+
+// Outcomes lists what a delivery can produce, in the order weights given
+// as an array follow: runs off the bat, a wicket, a wide and a no-ball.
+var Outcomes = []string{"0", "1", "2", "3", "4", "6", "W", "wd", "nb"}
+
+// DefaultWeights is used for a player given without weights: roughly a
+// T20 side scoring at eight an over and losing a wicket every 22 balls.
+var DefaultWeights = []float64{0.33, 0.36, 0.08, 0.01, 0.11, 0.04, 0.045, 0.02, 0.005}
+
+// dismissals are the ways a simulated wicket falls, with how often.
+var dismissals = []struct {
+	kind   string
+	weight float64
+}{
+	{"caught", 0.6},
+	{"bowled", 0.2},
+	{"lbw", 0.15},
+	{"stumped", 0.05},
+}
+
+// Player is a batter or bowler. Weights has one entry per Outcomes entry;
+// nil means DefaultWeights.
+type Player struct {
+	Name    string
+	Weights []float64
+}
+
+type Team struct {
+	Name    string
+	Batters []Player
+	Bowlers []Player
+}
+
+type Delivery struct {
+	Label     string // over and ball, "3.2"; extras repeat the label
+	Bowler    string
+	Batter    string
+	Outcome   string
+	Runs      int64
+	FreeHit   bool
+	Dismissal string
+	Score     int64
+	Wickets   int64
+}
+
+type BowlingFigures struct {
+	Name    string
+	Balls   int64
+	Runs    int64
+	Wickets int64
+}
+
+type InningsResult struct {
+	Innings    *object.Innings
+	Deliveries []Delivery
+	Bowling    []*BowlingFigures
+	Target     int64 // zero when batting first
+}
+
+// SimulateInnings plays batting against bowling for up to overs overs,
+// with no bowler bowling more than quota overs or two overs in a row. A
+// positive target ends the innings as soon as it is reached.
+func SimulateInnings(rng *rand.Rand, batting, bowling Team, overs, quota, target int64) (*InningsResult, error) {
+	if err := checkTeams(batting, bowling, overs, quota); err != nil {
+		return nil, err
+	}
+
+	order := make([]string, len(batting.Batters))
+	weights := map[string][]float64{}
+	for i, b := range batting.Batters {
+		order[i] = b.Name
+		weights[b.Name] = b.Weights
+	}
+	inn, err := object.NewInnings(batting.Name, order, overs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &InningsResult{Innings: inn, Target: target}
+	figures := map[string]*BowlingFigures{}
+	for _, b := range bowling.Bowlers {
+		figures[b.Name] = &BowlingFigures{Name: b.Name}
+	}
+
+	last, next := -1, 0
+	freeHit := false
+	for !inn.Over() && !(target > 0 && inn.Total >= target) {
+		over := inn.Balls / object.BallsPerOver
+		current := -1
+		for i := 0; i < len(bowling.Bowlers); i++ {
+			candidate := (next + i) % len(bowling.Bowlers)
+			if candidate != last && figures[bowling.Bowlers[candidate].Name].Balls < quota*object.BallsPerOver {
+				current = candidate
+				break
+			}
+		}
+		if current < 0 {
+			return nil, fmt.Errorf("no bowler of %s can bowl over %d", bowling.Name, over+1)
+		}
+		bowler := bowling.Bowlers[current]
+		figure := figures[bowler.Name]
+		if !contains(result.Bowling, figure) {
+			result.Bowling = append(result.Bowling, figure)
+		}
+
+		for inn.Balls/object.BallsPerOver == over && !inn.Over() && !(target > 0 && inn.Total >= target) {
+			d := Delivery{
+				Label:   fmt.Sprintf("%d.%d", over, inn.Balls%object.BallsPerOver+1),
+				Bowler:  bowler.Name,
+				Batter:  inn.Batters[inn.Striker].Name,
+				FreeHit: freeHit,
+			}
+			d.Outcome = Outcomes[pick(rng, combine(weights[d.Batter], bowler.Weights))]
+			before := inn.Total
+
+			freeHit = false
+			switch d.Outcome {
+			case "W":
+				if d.FreeHit {
+					// only a run out stands on a free hit; treat it as a dot
+					err = inn.Runs(0)
+				} else {
+					d.Dismissal = pickDismissal(rng)
+					err = inn.Wicket(d.Dismissal, 0, false)
+					figure.Wickets++
+				}
+			case "wd":
+				err = inn.Wide(0)
+				freeHit = d.FreeHit
+			case "nb":
+				err = inn.NoBall(0)
+				freeHit = true
+			default:
+				runs, _ := strconv.ParseInt(d.Outcome, 10, 64)
+				err = inn.Runs(runs)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if d.Outcome != "wd" && d.Outcome != "nb" {
+				figure.Balls++
+			}
+			d.Runs = inn.Total - before
+			figure.Runs += d.Runs
+			d.Score, d.Wickets = inn.Total, inn.Wickets
+			result.Deliveries = append(result.Deliveries, d)
+		}
+
+		last, next = current, current+1
+	}
+	return result, nil
+}
+
+func contains(figures []*BowlingFigures, figure *BowlingFigures) bool {
+	for _, f := range figures {
+		if f == figure {
+			return true
+		}
+	}
+	return false
+}
+
+func checkTeams(batting, bowling Team, overs, quota int64) error {
+	if overs < 1 {
+		return fmt.Errorf("overs must be positive, got %d", overs)
+	}
+	if quota < 1 {
+		return fmt.Errorf("bowler quota must be positive, got %d", quota)
+	}
+	if len(bowling.Bowlers) == 0 {
+		return fmt.Errorf("%s has no bowlers", bowling.Name)
+	}
+	if overs > 1 && len(bowling.Bowlers) < 2 {
+		return fmt.Errorf("%s needs at least 2 bowlers to bowl %d overs", bowling.Name, overs)
+	}
+	if int64(len(bowling.Bowlers))*quota < overs {
+		return fmt.Errorf("%d bowlers of %s with %d overs each can't bowl %d overs", len(bowling.Bowlers), bowling.Name, quota, overs)
+	}
+
+	seen := map[string]bool{}
+	for _, b := range bowling.Bowlers {
+		if seen[b.Name] {
+			return fmt.Errorf("%s appears twice in the bowlers of %s", b.Name, bowling.Name)
+		}
+		seen[b.Name] = true
+	}
+	for _, players := range [][]Player{batting.Batters, bowling.Bowlers} {
+		for _, p := range players {
+			if err := checkWeights(p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkWeights(p Player) error {
+	if p.Weights == nil {
+		return nil
+	}
+	if len(p.Weights) != len(Outcomes) {
+		return fmt.Errorf("%s needs %d outcome weights, got %d", p.Name, len(Outcomes), len(p.Weights))
+	}
+	total := 0.0
+	for _, w := range p.Weights {
+		if w < 0 {
+			return fmt.Errorf("%s has a negative outcome weight", p.Name)
+		}
+		total += w
+	}
+	if total == 0 {
+		return fmt.Errorf("%s has no outcome weights", p.Name)
+	}
+	return nil
+}
+
+// combine averages a batter's and a bowler's weights once each is
+// normalised, so neither side dominates by giving bigger numbers.
+func combine(batter, bowler []float64) []float64 {
+	if batter == nil {
+		batter = DefaultWeights
+	}
+	if bowler == nil {
+		bowler = DefaultWeights
+	}
+	batterTotal, bowlerTotal := sum(batter), sum(bowler)
+	combined := make([]float64, len(Outcomes))
+	for i := range combined {
+		combined[i] = (batter[i]/batterTotal + bowler[i]/bowlerTotal) / 2
+	}
+	return combined
+}
+
+func sum(weights []float64) float64 {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	return total
+}
+
+// pick draws an index with probability proportional to its weight.
+func pick(rng *rand.Rand, weights []float64) int {
+	r := rng.Float64() * sum(weights)
+	for i, w := range weights {
+		if r < w {
+			return i
+		}
+		r -= w
+	}
+	return len(weights) - 1
+}
+
+func pickDismissal(rng *rand.Rand) string {
+	weights := make([]float64, len(dismissals))
+	for i, d := range dismissals {
+		weights[i] = d.weight
+	}
+	return dismissals[pick(rng, weights)].kind
+}
+
+// Match is a limited-overs match; Teams[0] bats first. A Quota of zero
+// means the usual fifth of the overs, rounded up.
+type Match struct {
+	Teams [2]Team
+	Overs int64
+	Quota int64
+}
+
+type MatchResult struct {
+	Innings [2]*InningsResult
+	Winner  string // empty for a tie
+	Margin  string
+}
+
+func SimulateMatch(rng *rand.Rand, m Match) (*MatchResult, error) {
+	if m.Teams[0].Name == m.Teams[1].Name {
+		return nil, fmt.Errorf("both teams are called %s", m.Teams[0].Name)
+	}
+	quota := m.Quota
+	if quota == 0 {
+		quota = (m.Overs + 4) / 5
+	}
+
+	first, err := SimulateInnings(rng, m.Teams[0], m.Teams[1], m.Overs, quota, 0)
+	if err != nil {
+		return nil, err
+	}
+	target := first.Innings.Total + 1
+	second, err := SimulateInnings(rng, m.Teams[1], m.Teams[0], m.Overs, quota, target)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MatchResult{Innings: [2]*InningsResult{first, second}}
+	chase := second.Innings
+	switch {
+	case chase.Total >= target:
+		left := int64(len(chase.Batters)-1) - chase.Wickets
+		result.Winner = m.Teams[1].Name
+		result.Margin = fmt.Sprintf("%d %s", left, plural(left, "wicket"))
+	case chase.Total == target-1:
+		result.Margin = "tie"
+	default:
+		short := target - 1 - chase.Total
+		result.Winner = m.Teams[0].Name
+		result.Margin = fmt.Sprintf("%d %s", short, plural(short, "run"))
+	}
+	return result, nil
+}
+
+func plural(n int64, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+// Odds counts the results of a batch of simulated matches.
+type Odds struct {
+	Simulations int64
+	Wins        map[string]int64
+	Ties        int64
+}
+
+// WinProbability simulates m n times.
+func WinProbability(rng *rand.Rand, m Match, n int64) (*Odds, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("simulations must be positive, got %d", n)
+	}
+	odds := &Odds{Simulations: n, Wins: map[string]int64{m.Teams[0].Name: 0, m.Teams[1].Name: 0}}
+	for i := int64(0); i < n; i++ {
+		result, err := SimulateMatch(rng, m)
+		if err != nil {
+			return nil, err
+		}
+		if result.Winner == "" {
+			odds.Ties++
+		} else {
+			odds.Wins[result.Winner]++
+		}
+	}
+	return odds, nil
+}
@@ -0,0 +1,185 @@
+package sim
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func team(name string, batters, bowlers int) Team {
+	t := Team{Name: name}
+	for i := 0; i < batters; i++ {
+		t.Batters = append(t.Batters, Player{Name: name + string(rune('A'+i))})
+	}
+	for i := 0; i < bowlers; i++ {
+		t.Bowlers = append(t.Bowlers, Player{Name: name + string(rune('a'+i))})
+	}
+	return t
+}
+
+// only gives a player who produces nothing but outcome.
+func only(name, outcome string) Player {
+	weights := make([]float64, len(Outcomes))
+	for i, o := range Outcomes {
+		if o == outcome {
+			weights[i] = 1
+		}
+	}
+	return Player{Name: name, Weights: weights}
+}
+
+func TestSimulateInningsIsDeterministic(t *testing.T) {
+	a, b := team("A", 11, 5), team("B", 11, 5)
+
+	first, err := SimulateInnings(rand.New(rand.NewSource(42)), a, b, 20, 4, 0)
+	if err != nil {
+		t.Fatalf("SimulateInnings returned error: %s", err)
+	}
+	second, _ := SimulateInnings(rand.New(rand.NewSource(42)), a, b, 20, 4, 0)
+
+	if !reflect.DeepEqual(first.Deliveries, second.Deliveries) {
+		t.Errorf("the same seed gave different deliveries")
+	}
+	if first.Innings.Inspect() != second.Innings.Inspect() {
+		t.Errorf("the same seed gave different scorecards")
+	}
+}
+
+func TestSimulateInningsRespectsLimits(t *testing.T) {
+	a, b := team("A", 11, 5), team("B", 11, 5)
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 50; i++ {
+		result, err := SimulateInnings(rng, a, b, 20, 4, 0)
+		if err != nil {
+			t.Fatalf("SimulateInnings returned error: %s", err)
+		}
+		inn := result.Innings
+		if inn.Balls > 120 {
+			t.Fatalf("innings went past 20 overs: %s", inn.Overs())
+		}
+		if inn.Balls < 120 && inn.Wickets != 10 {
+			t.Fatalf("innings ended early at %d/%d in %s overs", inn.Total, inn.Wickets, inn.Overs())
+		}
+
+		var balls, runs int64
+		for _, f := range result.Bowling {
+			if f.Balls > 24 {
+				t.Fatalf("%s bowled %d balls, over the 4 over quota", f.Name, f.Balls)
+			}
+			balls += f.Balls
+			runs += f.Runs
+		}
+		if balls != inn.Balls || runs != inn.Total {
+			t.Fatalf("bowling figures add up to %d runs off %d balls, innings is %d off %d", runs, balls, inn.Total, inn.Balls)
+		}
+
+		for j := 1; j < len(result.Deliveries); j++ {
+			prev, d := result.Deliveries[j-1], result.Deliveries[j]
+			if over(prev.Label) != over(d.Label) && prev.Bowler == d.Bowler {
+				t.Fatalf("%s bowled consecutive overs at %s", d.Bowler, d.Label)
+			}
+		}
+	}
+}
+
+func over(label string) string {
+	return strings.SplitN(label, ".", 2)[0]
+}
+
+func TestSimulateInningsStopsWhenTargetIsReached(t *testing.T) {
+	batting := Team{Name: "A", Batters: []Player{only("A1", "6"), only("A2", "6")}}
+	bowling := Team{Name: "B", Bowlers: []Player{only("B1", "6"), only("B2", "6")}}
+
+	result, err := SimulateInnings(rand.New(rand.NewSource(1)), batting, bowling, 20, 10, 20)
+	if err != nil {
+		t.Fatalf("SimulateInnings returned error: %s", err)
+	}
+	if result.Innings.Total != 24 || result.Innings.Overs() != "0.4" {
+		t.Errorf("chase should end on 24 in 0.4 overs, got %d in %s", result.Innings.Total, result.Innings.Overs())
+	}
+}
+
+func TestFreeHit(t *testing.T) {
+	batting := Team{Name: "A", Batters: []Player{only("A1", "W"), only("A2", "W")}}
+	noBalls := Player{Name: "B1", Weights: make([]float64, len(Outcomes))}
+	noBalls.Weights[6], noBalls.Weights[8] = 1, 1
+	bowling := Team{Name: "B", Bowlers: []Player{noBalls, noBalls}}
+	bowling.Bowlers[1].Name = "B2"
+
+	rng := rand.New(rand.NewSource(3))
+	for i := 0; i < 20; i++ {
+		result, err := SimulateInnings(rng, batting, bowling, 2, 1, 0)
+		if err != nil {
+			t.Fatalf("SimulateInnings returned error: %s", err)
+		}
+		for j, d := range result.Deliveries {
+			wantFreeHit := j > 0 && result.Deliveries[j-1].Outcome == "nb"
+			if d.FreeHit != wantFreeHit {
+				t.Fatalf("delivery %d: free hit = %t, want %t", j, d.FreeHit, wantFreeHit)
+			}
+			if d.FreeHit && d.Dismissal != "" {
+				t.Fatalf("delivery %d: %s was out %s on a free hit", j, d.Batter, d.Dismissal)
+			}
+		}
+	}
+}
+
+func TestSimulateMatch(t *testing.T) {
+	strong := Team{Name: "Strong", Batters: []Player{only("S1", "6"), only("S2", "6")}, Bowlers: []Player{only("S3", "W"), only("S4", "W")}}
+	weak := Team{Name: "Weak", Batters: []Player{only("W1", "0"), only("W2", "0")}, Bowlers: []Player{only("W3", "0"), only("W4", "0")}}
+
+	result, err := SimulateMatch(rand.New(rand.NewSource(1)), Match{Teams: [2]Team{strong, weak}, Overs: 2})
+	if err != nil {
+		t.Fatalf("SimulateMatch returned error: %s", err)
+	}
+	if result.Winner != "Strong" || result.Innings[1].Target != result.Innings[0].Innings.Total+1 {
+		t.Errorf("wrong result. winner=%q margin=%q", result.Winner, result.Margin)
+	}
+
+	result, _ = SimulateMatch(rand.New(rand.NewSource(1)), Match{Teams: [2]Team{weak, strong}, Overs: 2})
+	if result.Winner != "Strong" || result.Margin != "1 wicket" {
+		t.Errorf("wrong result chasing. winner=%q margin=%q", result.Winner, result.Margin)
+	}
+}
+
+func TestWinProbability(t *testing.T) {
+	a, b := team("A", 11, 5), team("B", 11, 5)
+	m := Match{Teams: [2]Team{a, b}, Overs: 20}
+
+	odds, err := WinProbability(rand.New(rand.NewSource(9)), m, 100)
+	if err != nil {
+		t.Fatalf("WinProbability returned error: %s", err)
+	}
+	if odds.Wins["A"]+odds.Wins["B"]+odds.Ties != 100 {
+		t.Errorf("results don't add up to 100 simulations: %+v", odds)
+	}
+	again, _ := WinProbability(rand.New(rand.NewSource(9)), m, 100)
+	if !reflect.DeepEqual(odds, again) {
+		t.Errorf("the same seed gave different odds: %+v and %+v", odds, again)
+	}
+}
+
+func TestSimulationErrors(t *testing.T) {
+	a := team("A", 11, 5)
+	tests := []struct {
+		bowling Team
+		overs   int64
+		quota   int64
+		want    string
+	}{
+		{team("B", 11, 0), 20, 4, "B has no bowlers"},
+		{team("B", 11, 1), 20, 20, "B needs at least 2 bowlers to bowl 20 overs"},
+		{team("B", 11, 4), 20, 4, "4 bowlers of B with 4 overs each can't bowl 20 overs"},
+		{team("B", 11, 5), 0, 4, "overs must be positive, got 0"},
+		{Team{Name: "B", Bowlers: []Player{{Name: "x", Weights: []float64{1}}, {Name: "y"}}}, 2, 1, "x needs 9 outcome weights, got 1"},
+	}
+
+	for _, tt := range tests {
+		_, err := SimulateInnings(rand.New(rand.NewSource(1)), a, tt.bowling, tt.overs, tt.quota, 0)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("wrong error. want=%q, got=%v", tt.want, err)
+		}
+	}
+}
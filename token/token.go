@@ -5,12 +5,17 @@ type TokenType string
 type Token struct {
 	Type    TokenType
 	Literal string
+	Line    int // 1-based line the token starts on
+	Column  int // 1-based byte offset of the token within its line
 }
 
 const (
 	ILLEGAL = "NO_BALL"
 	EOF     = "MATCH_ENDED"
 
+	// Comments are collected by the lexer but never handed to the parser
+	COMMENT = "COMMENT"
+
 	// Identifiers + literals
 	IDENT  = "IDENT"
 	INT    = "INT"
@@ -27,10 +32,14 @@ const (
 	GT       = ">"
 	EQ       = "=="
 	NOT_EQ   = "!="
+	ARROW    = "->"
 
 	// Delimiters
 	COMMA     = ","
 	SEMICOLON = ";"
+	COLON     = ":"
+	DOT       = "."
+	ELLIPSIS  = "..."
 
 	LPAREN   = "("
 	RPAREN   = ")"
@@ -48,6 +57,10 @@ const (
 	APPEALOVERTURNED_ELSEIF = "APPEAL_OVERTURNED"
 	APPEALREJECTED_ELSE     = "APPEAL_REJECTED"
 	SIGNALDECISION_RETURN   = "SIGNAL_DECISION"
+	MACRO                   = "MACRO"
+	SQUAD                   = "SQUAD"
+	EXTENDS                 = "EXTENDS"
+	UMPIRE                  = "UMPIRE"
 )
 
 var keywords = map[string]TokenType{
@@ -59,6 +72,10 @@ var keywords = map[string]TokenType{
 	"appealoverturned": APPEALOVERTURNED_ELSEIF,
 	"appealrejected":   APPEALREJECTED_ELSE,
 	"signaldecision":   SIGNALDECISION_RETURN,
+	"macro":            MACRO,
+	"squad":            SQUAD,
+	"extends":          EXTENDS,
+	"umpire":           UMPIRE,
 }
 
 func LookupIdent(ident string) TokenType {